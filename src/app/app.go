@@ -0,0 +1,256 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+
+	"assets"
+	"glutil"
+)
+
+// Scene is a program run by an App. Init is called once the GL context is
+// current, then each frame runs any Updates that are due and a Render.
+type Scene interface {
+	Init(a *App) error
+
+	// Update moves the simulation on by dt, which is always the loop's Step
+	Update(dt float64)
+
+	// Render draws a frame, alpha of the way from the state before the last
+	// Update to the state after it
+	Render(alpha float64)
+
+	// Resize is called with the framebuffer size after Init and whenever it
+	// changes
+	Resize(width, height int)
+
+	// Shutdown is called before the context is destroyed if Init succeeded
+	Shutdown()
+}
+
+// FrameBeginner is a Scene that wants to know about every frame before its
+// updates run, e.g. to handle input. BeginFrame is given the measured frame
+// time and returns the time to simulate, which lets a scene substitute its
+// own clock.
+type FrameBeginner interface {
+	BeginFrame(frameTime float64) float64
+}
+
+// Base gives a Scene nothing to do for the hooks it does not need
+type Base struct{}
+
+func (Base) Update(dt float64)        {}
+func (Base) Resize(width, height int) {}
+func (Base) Shutdown()                {}
+
+// Config says how to create an App's context and how often it updates
+type Config struct {
+	glutil.ContextConfig
+
+	// UpdateRate is how many fixed Updates run per simulated second,
+	// DefaultUpdateRate if unset
+	UpdateRate float64
+
+	// Assets is a directory whose files take precedence over the embedded
+	// shaders and textures; see AssetFS. It defaults to the source directory
+	// of the embedded files, when there is one.
+	Assets string
+}
+
+// DefaultUpdateRate is the update rate of Configs that do not set one
+const DefaultUpdateRate = 60.0
+
+// NewConfig is a config for a vsynced window of the given size
+func NewConfig(title string, width, height int) Config {
+	return Config{
+		ContextConfig: glutil.ContextConfig{Title: title, Width: width, Height: height, VSync: true},
+		UpdateRate:    DefaultUpdateRate,
+		Assets:        assets.Dir,
+	}
+}
+
+// RegisterFlags adds the context flags, -rate and -assets to a flag set
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	c.ContextConfig.RegisterFlags(fs)
+	fs.Float64Var(&c.UpdateRate, "rate", c.UpdateRate, "fixed updates per second, independent of the frame rate")
+	fs.StringVar(&c.Assets, "assets", c.Assets, "directory of shaders/ and textures/ used before the built-in copies (empty for none)")
+}
+
+// AssetFS lays a directory on disk over the embedded assets. Files found on
+// disk are used, and hot reloaded, in place of the embedded ones; anything
+// missing falls through to the copy built into the program.
+func AssetFS(dir string) fs.FS {
+	if dir == "" {
+		return assets.FS
+	}
+	if info, err := os.Stat(filepath.Join(dir, "shaders")); err != nil || !info.IsDir() {
+		fmt.Printf("Warning: %s has no shaders/ directory, shader edits will not be reloaded\n", dir)
+	}
+	return glutil.Overlay{os.DirFS(dir), assets.FS}
+}
+
+// App is a running Scene with its context and loop
+type App struct {
+	glutil.Context
+	Config Config
+	Loop   *Loop
+
+	scene Scene
+	quit  bool
+
+	// Direction of a scene switch asked for this frame, and whether the
+	// switch key was down last frame
+	switching  int
+	switchHeld bool
+}
+
+// Quit stops the App after the current frame. Closing the window does the
+// same.
+func (a *App) Quit() {
+	a.quit = true
+}
+
+// Run creates a context, runs a scene in it until it is closed, and cleans
+// up afterwards
+func Run(c Config, s Scene) error {
+	return run(c, []Entry{{Name: c.Title, New: func() Scene { return s }}}, 0)
+}
+
+// Cycle runs entries[first] like Run, except that Tab switches to the next
+// entry in the same window and Shift+Tab to the previous one
+func Cycle(c Config, entries []Entry, first int) error {
+	return run(c, entries, first)
+}
+
+func run(c Config, entries []Entry, current int) error {
+	if c.UpdateRate <= 0 {
+		c.UpdateRate = DefaultUpdateRate
+	}
+
+	glutil.Assets = AssetFS(c.Assets)
+
+	ctx, err := glutil.NewContext(c.ContextConfig)
+	if err != nil {
+		return err
+	}
+	defer ctx.Destroy()
+
+	a := &App{Context: ctx, Config: c}
+	a.Viewport().OnResize(func(width, height int) {
+		if a.scene != nil {
+			a.scene.Resize(width, height)
+		}
+	})
+
+	for {
+		e := entries[current]
+		if err := a.start(e); err != nil {
+			return fmt.Errorf("%s: %v", e.Name, err)
+		}
+		a.runScene(len(entries) > 1)
+		a.scene.Shutdown()
+		a.scene = nil
+
+		if a.switching == 0 {
+			return nil
+		}
+		current = (current + a.switching + len(entries)) % len(entries)
+		a.reset()
+	}
+}
+
+// start makes and initialises a scene, with a fresh loop so it starts from
+// time 0 of its simulation
+func (a *App) start(e Entry) error {
+	if w := a.Window(); w != nil && len(e.Name) > 0 && e.Name != a.Config.Title {
+		w.SetTitle(a.Config.Title + " - " + e.Name)
+	}
+
+	a.Loop = NewLoop(a.Config.UpdateRate)
+	a.switching = 0
+
+	s := e.New()
+	if err := s.Init(a); err != nil {
+		return err
+	}
+	a.scene = s
+	v := a.Viewport()
+	s.Resize(v.Width, v.Height)
+	return nil
+}
+
+// runScene runs the current scene's frames until the App is closed or, if
+// switching is allowed, another scene is asked for
+func (a *App) runScene(switching bool) {
+	s := a.scene
+	beginner, _ := s.(FrameBeginner)
+	lastTime := a.Time()
+	for !a.ShouldClose() && !a.quit {
+		a.PollEvents()
+		if switching && a.pollSwitch() {
+			return
+		}
+
+		currTime := a.Time()
+		frameTime := currTime - lastTime
+		lastTime = currTime
+		if beginner != nil {
+			frameTime = beginner.BeginFrame(frameTime)
+		}
+
+		alpha := a.Loop.Advance(frameTime, s.Update)
+		s.Render(alpha)
+
+		a.SwapBuffers()
+	}
+}
+
+// pollSwitch watches for Tab going down. Scenes own the window's callbacks,
+// so the key is polled rather than listened for.
+func (a *App) pollSwitch() bool {
+	w := a.Window()
+	if w == nil {
+		return false
+	}
+	held := w.GetKey(glfw.KeyTab) == glfw.Press
+	pressed := held && !a.switchHeld
+	a.switchHeld = held
+	if !pressed {
+		return false
+	}
+
+	a.switching = 1
+	if w.GetKey(glfw.KeyLeftShift) == glfw.Press || w.GetKey(glfw.KeyRightShift) == glfw.Press {
+		a.switching = -1
+	}
+	return true
+}
+
+// reset puts the window and GL state a scene may have changed back to their
+// defaults, so the next scene starts as it would in a window of its own
+func (a *App) reset() {
+	if w := a.Window(); w != nil {
+		w.SetKeyCallback(nil)
+		w.SetMouseButtonCallback(nil)
+		w.SetCursorPosCallback(nil)
+		w.SetScrollCallback(nil)
+		w.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+	}
+	glfw.SetJoystickCallback(nil)
+
+	gl.UseProgram(0)
+	gl.BindVertexArray(0)
+	for unit := uint32(0); unit < 16; unit++ {
+		gl.ActiveTexture(gl.TEXTURE0 + unit)
+		gl.BindTexture(gl.TEXTURE_2D, 0)
+	}
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.Disable(gl.DEPTH_TEST)
+	gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+}
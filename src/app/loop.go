@@ -0,0 +1,84 @@
+// Package app holds what the tutorials share about running a program, such
+// as stepping a simulation at a fixed rate
+package app
+
+// DefaultMaxFrameTime is the longest frame a Loop counts, in seconds
+const DefaultMaxFrameTime = 0.25
+
+// Loop steps a simulation at a fixed rate however fast frames are drawn, so
+// movement does not depend on the frame rate or jump when a frame hitches.
+// Frame times build up in an accumulator and each whole Step of it runs one
+// update. What is left over is how far the frame falls between the last two
+// updates, which rendering uses to interpolate between their states.
+type Loop struct {
+	// Step is the simulated time each update covers, in seconds
+	Step float64
+
+	// MaxFrameTime caps how much time one frame adds, so a long stall runs a
+	// few updates rather than a burst that stalls the next frame in turn.
+	// 0 means no cap.
+	MaxFrameTime float64
+
+	// Paused stops updates until it is cleared or StepOnce is called
+	Paused bool
+
+	// Time is the simulated time so far and Updates the updates run
+	Time    float64
+	Updates int
+
+	accumulator float64
+	steps       int
+}
+
+// NewLoop makes a loop running rate updates per second
+func NewLoop(rate float64) *Loop {
+	return &Loop{Step: 1.0 / rate, MaxFrameTime: DefaultMaxFrameTime}
+}
+
+// Advance adds a frame's time and runs the updates it makes due, passing
+// each one Step. It returns the interpolation alpha, from 0 at the state
+// before the last update to 1 at the state after it.
+func (l *Loop) Advance(frameTime float64, update func(dt float64)) float64 {
+	if l.MaxFrameTime > 0 && frameTime > l.MaxFrameTime {
+		frameTime = l.MaxFrameTime
+	}
+
+	if l.Paused {
+		for ; l.steps > 0; l.steps-- {
+			l.update(update)
+		}
+		return l.Alpha()
+	}
+
+	l.accumulator += frameTime
+	for l.accumulator >= l.Step {
+		l.accumulator -= l.Step
+		l.update(update)
+	}
+	return l.Alpha()
+}
+
+func (l *Loop) update(update func(dt float64)) {
+	update(l.Step)
+	l.Time += l.Step
+	l.Updates++
+}
+
+// Alpha is how far between the last two updates the current frame falls
+func (l *Loop) Alpha() float64 {
+	return l.accumulator / l.Step
+}
+
+// TogglePause pauses or resumes updates. Time spent paused is not made up
+// afterwards.
+func (l *Loop) TogglePause() {
+	l.Paused = !l.Paused
+	l.steps = 0
+}
+
+// StepOnce pauses the loop if it is running and runs a single update at the
+// next Advance
+func (l *Loop) StepOnce() {
+	l.Paused = true
+	l.steps++
+}
@@ -0,0 +1,78 @@
+package app
+
+import "testing"
+
+func TestLoopAdvance(t *testing.T) {
+	// Steps and frame times are powers of two so the sums are exact
+	tests := []struct {
+		name     string
+		max      float64
+		paused   bool
+		stepOnce int
+		frames   []float64
+		updates  int
+		alpha    float64
+	}{
+		{name: "one update per step", frames: []float64{0.25, 0.25}, updates: 2},
+		{name: "several updates in one frame", frames: []float64{0.75}, updates: 3},
+		{name: "leftover sets alpha", frames: []float64{0.375}, updates: 1, alpha: 0.5},
+		{name: "accumulates across frames", frames: []float64{0.125, 0.125, 0.0625}, updates: 1, alpha: 0.25},
+		{name: "short frames run nothing", frames: []float64{0.0625}, alpha: 0.25},
+		{name: "max frame time clamps", max: 0.5, frames: []float64{2}, updates: 2},
+		{name: "default max frame time", max: DefaultMaxFrameTime, frames: []float64{10, 10}, updates: 2},
+		{name: "no cap", frames: []float64{2}, updates: 8},
+		{name: "paused", paused: true, frames: []float64{1, 1}},
+		{name: "step once", stepOnce: 1, frames: []float64{1, 1}, updates: 1},
+		{name: "step twice", stepOnce: 2, frames: []float64{0.0625}, updates: 2},
+		{name: "step while paused", paused: true, stepOnce: 1, frames: []float64{1}, updates: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLoop(4)
+			l.MaxFrameTime = tt.max
+			l.Paused = tt.paused
+			for i := 0; i < tt.stepOnce; i++ {
+				l.StepOnce()
+			}
+
+			updates := 0
+			var alpha float64
+			for _, frame := range tt.frames {
+				alpha = l.Advance(frame, func(dt float64) {
+					if dt != l.Step {
+						t.Errorf("update got dt %v, want %v", dt, l.Step)
+					}
+					updates++
+				})
+			}
+
+			if updates != tt.updates || l.Updates != tt.updates {
+				t.Errorf("ran %d updates (Updates %d), want %d", updates, l.Updates, tt.updates)
+			}
+			if want := float64(tt.updates) * l.Step; l.Time != want {
+				t.Errorf("Time %v, want %v", l.Time, want)
+			}
+			if alpha != tt.alpha || l.Alpha() != tt.alpha {
+				t.Errorf("alpha %v (Alpha %v), want %v", alpha, l.Alpha(), tt.alpha)
+			}
+			if l.Paused != (tt.paused || tt.stepOnce > 0) {
+				t.Errorf("Paused %v after the frames", l.Paused)
+			}
+		})
+	}
+}
+
+func TestLoopTogglePause(t *testing.T) {
+	l := NewLoop(4)
+	l.StepOnce()
+	l.TogglePause()
+	if l.Paused {
+		t.Fatal("still paused after TogglePause")
+	}
+	l.TogglePause()
+	l.Advance(1, func(float64) {})
+	if l.Updates != 0 {
+		t.Errorf("a step pending before TogglePause ran, Updates %d", l.Updates)
+	}
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+)
+
+// GLFW and GL calls must all come from the main thread
+func init() {
+	runtime.LockOSThread()
+}
+
+// Entry is a registered scene and how to make one
+type Entry struct {
+	Name        string
+	Description string
+
+	// Width and Height are the window size the scene was written for
+	Width  int
+	Height int
+
+	// Flags, if set, adds the scene's own flags to the program's flag set.
+	// Scenes made by New after parsing start with the values given.
+	Flags func(fs *flag.FlagSet)
+
+	New func() Scene
+}
+
+var registry = make(map[string]Entry)
+
+// Register adds a scene to those Scenes lists. Scene packages call it from
+// init, so importing one makes it available.
+func Register(e Entry) {
+	if _, ok := registry[e.Name]; ok {
+		panic(fmt.Sprintf("app: scene %s registered twice", e.Name))
+	}
+	registry[e.Name] = e
+}
+
+// Scenes lists the registered scenes in name order
+func Scenes() []Entry {
+	entries := make([]Entry, 0, len(registry))
+	for _, e := range registry {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// Lookup finds a registered scene by name
+func Lookup(name string) (Entry, bool) {
+	e, ok := registry[name]
+	return e, ok
+}
+
+// Main is the whole of a program that runs one registered scene, taking its
+// settings from the command line
+func Main(name string) {
+	e, ok := Lookup(name)
+	if !ok {
+		fmt.Printf("No scene %s\n", name)
+		os.Exit(1)
+	}
+
+	config := NewConfig(e.Name, e.Width, e.Height)
+	config.RegisterFlags(flag.CommandLine)
+	if e.Flags != nil {
+		e.Flags(flag.CommandLine)
+	}
+	flag.Parse()
+
+	if err := Run(config, e.New()); err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,32 @@
+// Package assets embeds the tutorials' shaders and textures, so the programs
+// run from any directory without the source tree
+package assets
+
+import (
+	"embed"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// FS holds shaders/ and textures/
+//
+//go:embed shaders textures
+var FS embed.FS
+
+// Dir is the directory holding the sources of FS when the program runs on the
+// machine it was built on, so edits there can be picked up live. It is empty
+// when that directory is not around.
+var Dir = sourceDir()
+
+func sourceDir() string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok || !filepath.IsAbs(file) {
+		return ""
+	}
+	dir := filepath.Dir(file)
+	if _, err := os.Stat(filepath.Join(dir, "shaders")); err != nil {
+		return ""
+	}
+	return dir
+}
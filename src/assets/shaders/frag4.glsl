@@ -7,6 +7,9 @@ out vec4 color;
 uniform sampler2D texture1;
 uniform sampler2D texture2;
 
+// Set by model.Draw for each material; white for everything else
+uniform vec4 baseColor = vec4(1.0);
+
 void main() {
-    color = mix(texture(texture1, texCoord), texture(texture2, texCoord), 0.2);
+    color = mix(texture(texture1, texCoord), texture(texture2, texCoord), 0.2) * baseColor;
 }
\ No newline at end of file
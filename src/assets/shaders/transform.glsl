@@ -0,0 +1,9 @@
+#pragma once
+
+uniform mat4 model;
+uniform mat4 view;
+uniform mat4 projection;
+
+vec4 transform(vec3 position) {
+    return projection * view * model * vec4(position, 1.0);
+}
@@ -3,13 +3,11 @@
 layout (location = 0) in vec3 position;
 layout (location = 1) in vec2 texture;
 
-uniform mat4 model;
-uniform mat4 view;
-uniform mat4 projection;
+#include "transform.glsl"
 
 out vec2 texCoord;
 
 void main() {
-    gl_Position = projection * view * model * vec4(position, 1.0);
+    gl_Position = transform(position);
     texCoord = texture;
 }
\ No newline at end of file
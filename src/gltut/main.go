@@ -0,0 +1,97 @@
+// Gltut lists and runs the tutorial scenes from one program:
+//
+//	gltut                    list the scenes
+//	gltut gl4                run gl4; Tab and Shift+Tab cycle through the rest
+//	gltut gl5 -model duck.glb
+//
+// Scene packages register themselves when imported, so new scenes only need
+// adding to the imports below.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"app"
+	_ "scenes/gl1"
+	_ "scenes/gl2"
+	_ "scenes/gl3"
+	_ "scenes/gl4"
+	_ "scenes/gl5"
+)
+
+var (
+	width  = flag.Int("width", 0, "window width (0 = the scene's own)")
+	height = flag.Int("height", 0, "window height (0 = the scene's own)")
+	list   = flag.Bool("list", false, "list the scenes and exit")
+)
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: gltut [flags] [scene [scene flags]]\n\n")
+	listScenes()
+	fmt.Fprintf(flag.CommandLine.Output(), "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+func listScenes() {
+	w := tabwriter.NewWriter(flag.CommandLine.Output(), 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Scenes:\n")
+	for _, e := range app.Scenes() {
+		fmt.Fprintf(w, "  %s\t%s\n", e.Name, e.Description)
+	}
+	w.Flush()
+}
+
+func main() {
+	config := app.NewConfig("gltut", 0, 0)
+	config.RegisterFlags(flag.CommandLine)
+	flag.Usage = usage
+	flag.Parse()
+
+	if *list || flag.NArg() == 0 {
+		flag.CommandLine.SetOutput(os.Stdout)
+		listScenes()
+		return
+	}
+
+	name := flag.Arg(0)
+	scenes := app.Scenes()
+	first := -1
+	for i, e := range scenes {
+		if e.Name == name {
+			first = i
+		}
+	}
+	if first < 0 {
+		fmt.Printf("No scene %s\n", name)
+		listScenes()
+		os.Exit(1)
+	}
+
+	// Flags for the scene itself, such as gl5's -model, follow its name
+	if e := scenes[first]; e.Flags != nil {
+		e.Flags(flag.CommandLine)
+	}
+	if err := flag.CommandLine.Parse(flag.Args()[1:]); err != nil {
+		os.Exit(2)
+	}
+	if flag.NArg() > 0 {
+		fmt.Printf("Unexpected arguments after %s: %v\n", name, flag.Args())
+		os.Exit(2)
+	}
+
+	config.Width, config.Height = scenes[first].Width, scenes[first].Height
+	if *width > 0 {
+		config.Width = *width
+	}
+	if *height > 0 {
+		config.Height = *height
+	}
+
+	if err := app.Cycle(config, scenes, first); err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+}
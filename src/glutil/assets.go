@@ -0,0 +1,66 @@
+package glutil
+
+import (
+	"errors"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Assets is the file system shaders, textures and models are loaded from.
+// It starts as the working directory; programs can swap in an embedded copy
+// of their assets or an Overlay of several.
+var Assets fs.FS = os.DirFS(".")
+
+// Overlay is a file system made of layers, each name opening from the first
+// layer that has it. Putting a directory on disk over an embedded copy lets
+// files be edited, and hot reloaded, without rebuilding.
+type Overlay []fs.FS
+
+// Open opens name from the first layer it exists in
+func (o Overlay) Open(name string) (fs.File, error) {
+	for _, layer := range o {
+		f, err := layer.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// assetName turns a file name into a name in Assets, or reports that it
+// lies outside them. Absolute paths and paths above the root cannot be
+// named in an fs.FS, so those are left to the disk.
+func assetName(filename string) (string, bool) {
+	name := filepath.ToSlash(filepath.Clean(filename))
+	return name, fs.ValidPath(name)
+}
+
+// OpenAsset opens a file from Assets, or from disk if the name is absolute
+// or climbs out of the asset root
+func OpenAsset(filename string) (fs.File, error) {
+	if name, ok := assetName(filename); ok {
+		return Assets.Open(name)
+	}
+	return os.Open(filename)
+}
+
+// ReadAsset reads a whole file the way OpenAsset finds it
+func ReadAsset(filename string) ([]byte, error) {
+	if name, ok := assetName(filename); ok {
+		return fs.ReadFile(Assets, name)
+	}
+	return ioutil.ReadFile(filename)
+}
+
+// StatAsset describes a file the way OpenAsset finds it
+func StatAsset(filename string) (fs.FileInfo, error) {
+	if name, ok := assetName(filename); ok {
+		return fs.Stat(Assets, name)
+	}
+	return os.Stat(filename)
+}
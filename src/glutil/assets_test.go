@@ -0,0 +1,58 @@
+package glutil
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+// brokenFS fails every open with something other than fs.ErrNotExist
+type brokenFS struct{}
+
+func (brokenFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+}
+
+func TestOverlay(t *testing.T) {
+	disk := fstest.MapFS{
+		"shaders/a.glsl": {Data: []byte("disk a")},
+	}
+	embedded := fstest.MapFS{
+		"shaders/a.glsl": {Data: []byte("embedded a")},
+		"shaders/b.glsl": {Data: []byte("embedded b")},
+	}
+
+	tests := []struct {
+		name    string
+		overlay Overlay
+		file    string
+		want    string
+		err     error
+	}{
+		{"first layer wins", Overlay{disk, embedded}, "shaders/a.glsl", "disk a", nil},
+		{"falls back to later layers", Overlay{disk, embedded}, "shaders/b.glsl", "embedded b", nil},
+		{"order decides", Overlay{embedded, disk}, "shaders/a.glsl", "embedded a", nil},
+		{"missing everywhere", Overlay{disk, embedded}, "shaders/c.glsl", "", fs.ErrNotExist},
+		{"no layers", Overlay{}, "shaders/a.glsl", "", fs.ErrNotExist},
+		{"other errors stop the lookup", Overlay{brokenFS{}, embedded}, "shaders/a.glsl", "", fs.ErrPermission},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := fs.ReadFile(tt.overlay, tt.file)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("got error %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("read %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
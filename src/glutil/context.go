@@ -0,0 +1,241 @@
+package glutil
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// Context is a current OpenGL 4.1 core context and the surface it draws to,
+// either a window or an offscreen framebuffer
+type Context interface {
+	// Window is nil for headless contexts
+	Window() *glfw.Window
+	Viewport() *Viewport
+
+	// Time is seconds since the context was created. Headless contexts run
+	// on a fixed clock of HeadlessFrameRate frames per second so their
+	// output does not depend on how fast the machine is.
+	Time() float64
+
+	ShouldClose() bool
+	PollEvents()
+	SwapBuffers()
+	Destroy()
+}
+
+// HeadlessFrameRate is the simulated frame rate of headless contexts
+var HeadlessFrameRate = 60.0
+
+// ContextConfig says what kind of context NewContext creates
+type ContextConfig struct {
+	Title  string
+	Width  int
+	Height int
+
+	// GLMajor and GLMinor are the core profile version to ask for, 4.1 if
+	// unset. The gl package's functions are loaded for 4.1, so asking for
+	// less only works where the driver provides them anyway.
+	GLMajor int
+	GLMinor int
+
+	// VSync waits for the display's refresh before swapping buffers, and
+	// Samples asks for a multisampled window. Neither applies headless.
+	VSync   bool
+	Samples int
+
+	// Headless renders into a framebuffer without opening a window, using
+	// a surfaceless EGL display. Mesa's llvmpipe needs no GPU for this.
+	Headless bool
+
+	// Frames stops the program after that many frames; 0 runs until the
+	// window is closed. A headless context with no limit renders one frame.
+	Frames int
+
+	// Output is a PNG file the last frame is written to when Frames is set
+	Output string
+}
+
+// RegisterFlags adds -headless, -frames, -out, -vsync and -samples to a flag
+// set, defaulting to the config's current values
+func (c *ContextConfig) RegisterFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.VSync, "vsync", c.VSync, "wait for the display's refresh before each new frame")
+	fs.IntVar(&c.Samples, "samples", c.Samples, "multisample anti-aliasing samples per pixel (0 = off)")
+	fs.BoolVar(&c.Headless, "headless", c.Headless, "render offscreen without a window")
+	fs.IntVar(&c.Frames, "frames", c.Frames, "exit after this many frames (0 = until closed)")
+	fs.StringVar(&c.Output, "out", c.Output, "write the last frame to this PNG file")
+}
+
+// NewContext creates a context, makes it current and loads the GL functions
+func NewContext(c ContextConfig) (Context, error) {
+	if c.GLMajor == 0 {
+		c.GLMajor, c.GLMinor = 4, 1
+	}
+	if c.Headless {
+		return newHeadlessContext(c)
+	}
+	return newWindowContext(c)
+}
+
+// frameCounter stops a context after a number of frames and saves the last one
+type frameCounter struct {
+	frames int
+	limit  int
+	output string
+}
+
+func (f *frameCounter) done() bool {
+	return f.limit > 0 && f.frames >= f.limit
+}
+
+// endFrame is called once a frame is drawn, before it is presented
+func (f *frameCounter) endFrame(v *Viewport) {
+	f.frames++
+	if f.output == "" || f.frames != f.limit {
+		return
+	}
+	if err := SavePNG(f.output, ReadPixels(v.Width, v.Height)); err != nil {
+		fmt.Printf("Failed to save frame: %v\n", err)
+		return
+	}
+	fmt.Printf("Saved frame %d to %s\n", f.frames, f.output)
+}
+
+type windowContext struct {
+	frameCounter
+	window   *glfw.Window
+	viewport *Viewport
+}
+
+func newWindowContext(c ContextConfig) (*windowContext, error) {
+	if err := glfw.Init(); err != nil {
+		return nil, err
+	}
+
+	glfw.WindowHint(glfw.ContextVersionMajor, c.GLMajor)
+	glfw.WindowHint(glfw.ContextVersionMinor, c.GLMinor)
+	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	glfw.WindowHint(glfw.Resizable, glfw.True)
+	glfw.WindowHint(glfw.Samples, c.Samples)
+
+	window, err := glfw.CreateWindow(c.Width, c.Height, c.Title, nil, nil)
+	if err != nil {
+		glfw.Terminate()
+		return nil, err
+	}
+
+	window.MakeContextCurrent()
+	if c.VSync {
+		glfw.SwapInterval(1)
+	} else {
+		glfw.SwapInterval(0)
+	}
+
+	if err := gl.Init(); err != nil {
+		window.Destroy()
+		glfw.Terminate()
+		return nil, err
+	}
+	if c.Samples > 0 {
+		gl.Enable(gl.MULTISAMPLE)
+	}
+
+	fmt.Printf("%s %s\n", gl.GoStr(gl.GetString(gl.RENDERER)), gl.GoStr(gl.GetString(gl.VERSION)))
+
+	return &windowContext{
+		frameCounter: frameCounter{limit: c.Frames, output: c.Output},
+		window:       window,
+		viewport:     NewViewport(window),
+	}, nil
+}
+
+func (w *windowContext) Window() *glfw.Window { return w.window }
+func (w *windowContext) Viewport() *Viewport  { return w.viewport }
+func (w *windowContext) Time() float64        { return glfw.GetTime() }
+func (w *windowContext) PollEvents()          { glfw.PollEvents() }
+
+func (w *windowContext) ShouldClose() bool {
+	return w.window.ShouldClose() || w.done()
+}
+
+func (w *windowContext) SwapBuffers() {
+	w.endFrame(w.viewport)
+	w.window.SwapBuffers()
+}
+
+func (w *windowContext) Destroy() {
+	w.window.Destroy()
+	glfw.Terminate()
+}
+
+// headlessContext draws into a framebuffer on a context with no surface
+type headlessContext struct {
+	frameCounter
+	egl         *eglContext
+	framebuffer *Framebuffer
+	viewport    *Viewport
+}
+
+func newHeadlessContext(c ContextConfig) (*headlessContext, error) {
+	egl, err := newEGLContext(c.GLMajor, c.GLMinor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := gl.InitWithProcAddrFunc(egl.procAddress); err != nil {
+		egl.destroy()
+		return nil, err
+	}
+
+	fmt.Printf("%s %s (headless)\n", gl.GoStr(gl.GetString(gl.RENDERER)), gl.GoStr(gl.GetString(gl.VERSION)))
+
+	fb, err := NewFramebuffer(c.Width, c.Height)
+	if err != nil {
+		egl.destroy()
+		return nil, err
+	}
+	fb.Bind()
+
+	h := &headlessContext{
+		frameCounter: frameCounter{limit: c.Frames, output: c.Output},
+		egl:          egl,
+		framebuffer:  fb,
+		viewport:     &Viewport{},
+	}
+	if h.limit <= 0 {
+		h.limit = 1
+	}
+
+	h.viewport.Resize(c.Width, c.Height)
+	h.viewport.OnResize(func(width, height int) {
+		if width == fb.Width && height == fb.Height {
+			return
+		}
+		if err := fb.Resize(width, height); err != nil {
+			fmt.Printf("%v\n", err)
+		}
+	})
+	return h, nil
+}
+
+// Framebuffer is where a headless context's frames end up
+func (h *headlessContext) Framebuffer() *Framebuffer { return h.framebuffer }
+
+func (h *headlessContext) Window() *glfw.Window { return nil }
+func (h *headlessContext) Viewport() *Viewport  { return h.viewport }
+func (h *headlessContext) Time() float64        { return float64(h.frames) / HeadlessFrameRate }
+func (h *headlessContext) ShouldClose() bool    { return h.done() }
+func (h *headlessContext) PollEvents()          {}
+
+func (h *headlessContext) SwapBuffers() {
+	h.endFrame(h.viewport)
+	gl.Finish()
+}
+
+func (h *headlessContext) Destroy() {
+	h.framebuffer.Delete()
+	h.egl.destroy()
+}
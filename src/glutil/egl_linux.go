@@ -0,0 +1,108 @@
+package glutil
+
+/*
+#cgo LDFLAGS: -lEGL
+#include <stdlib.h>
+#include <EGL/egl.h>
+#include <EGL/eglext.h>
+
+// Prefers Mesa's surfaceless platform, which needs neither a display server
+// nor a GPU, and falls back to the default display
+static EGLDisplay openDisplay(void) {
+	PFNEGLGETPLATFORMDISPLAYEXTPROC getPlatformDisplay =
+		(PFNEGLGETPLATFORMDISPLAYEXTPROC)eglGetProcAddress("eglGetPlatformDisplayEXT");
+	if (getPlatformDisplay != NULL) {
+		EGLDisplay d = getPlatformDisplay(EGL_PLATFORM_SURFACELESS_MESA, EGL_DEFAULT_DISPLAY, NULL);
+		if (d != EGL_NO_DISPLAY) {
+			return d;
+		}
+	}
+	return eglGetDisplay(EGL_DEFAULT_DISPLAY);
+}
+
+static const char *createContext(EGLDisplay *display, EGLContext *context, EGLint glMajor, EGLint glMinor) {
+	EGLint major, minor, count;
+	EGLConfig config;
+
+	// Nothing is drawn to an EGL surface, so accept configs of any surface type
+	static const EGLint configAttribs[] = {
+		EGL_SURFACE_TYPE, 0,
+		EGL_RENDERABLE_TYPE, EGL_OPENGL_BIT,
+		EGL_NONE
+	};
+	const EGLint contextAttribs[] = {
+		EGL_CONTEXT_MAJOR_VERSION, glMajor,
+		EGL_CONTEXT_MINOR_VERSION, glMinor,
+		EGL_CONTEXT_OPENGL_PROFILE_MASK, EGL_CONTEXT_OPENGL_CORE_PROFILE_BIT,
+		EGL_NONE
+	};
+
+	*display = openDisplay();
+	if (*display == EGL_NO_DISPLAY) {
+		return "no EGL display";
+	}
+	if (!eglInitialize(*display, &major, &minor)) {
+		return "eglInitialize failed";
+	}
+	if (!eglBindAPI(EGL_OPENGL_API)) {
+		eglTerminate(*display);
+		return "EGL display does not support desktop OpenGL";
+	}
+	if (!eglChooseConfig(*display, configAttribs, &config, 1, &count) || count == 0) {
+		eglTerminate(*display);
+		return "no EGL config supports OpenGL";
+	}
+
+	*context = eglCreateContext(*display, config, EGL_NO_CONTEXT, contextAttribs);
+	if (*context == EGL_NO_CONTEXT) {
+		eglTerminate(*display);
+		return "cannot create an OpenGL core context of the requested version";
+	}
+	if (!eglMakeCurrent(*display, EGL_NO_SURFACE, EGL_NO_SURFACE, *context)) {
+		eglDestroyContext(*display, *context);
+		eglTerminate(*display);
+		return "cannot make a surfaceless context current";
+	}
+	return NULL;
+}
+
+static void destroyContext(EGLDisplay display, EGLContext context) {
+	eglMakeCurrent(display, EGL_NO_SURFACE, EGL_NO_SURFACE, EGL_NO_CONTEXT);
+	eglDestroyContext(display, context);
+	eglTerminate(display);
+}
+
+static void *procAddress(const char *name) {
+	return (void *)eglGetProcAddress(name);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// eglContext is a current EGL context with no surface
+type eglContext struct {
+	display C.EGLDisplay
+	context C.EGLContext
+}
+
+func newEGLContext(major, minor int) (*eglContext, error) {
+	e := &eglContext{}
+	if msg := C.createContext(&e.display, &e.context, C.EGLint(major), C.EGLint(minor)); msg != nil {
+		return nil, fmt.Errorf("headless context: %s", C.GoString(msg))
+	}
+	return e, nil
+}
+
+func (e *eglContext) procAddress(name string) unsafe.Pointer {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	return C.procAddress(cname)
+}
+
+func (e *eglContext) destroy() {
+	C.destroyContext(e.display, e.context)
+}
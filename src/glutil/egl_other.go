@@ -0,0 +1,17 @@
+//go:build !linux
+
+package glutil
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+type eglContext struct{}
+
+func newEGLContext(major, minor int) (*eglContext, error) {
+	return nil, fmt.Errorf("headless context: only supported on Linux")
+}
+
+func (e *eglContext) procAddress(name string) unsafe.Pointer { return nil }
+func (e *eglContext) destroy()                               {}
@@ -0,0 +1,72 @@
+package glutil
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Framebuffer is an offscreen render target with an RGBA colour texture and a
+// depth/stencil renderbuffer
+type Framebuffer struct {
+	ID     uint32
+	Color  uint32
+	Depth  uint32
+	Width  int
+	Height int
+}
+
+// NewFramebuffer creates a framebuffer of the given size. The previously bound
+// framebuffer stays bound.
+func NewFramebuffer(width, height int) (*Framebuffer, error) {
+	f := &Framebuffer{}
+	gl.GenFramebuffers(1, &f.ID)
+	gl.GenTextures(1, &f.Color)
+	gl.GenRenderbuffers(1, &f.Depth)
+
+	if err := f.Resize(width, height); err != nil {
+		f.Delete()
+		return nil, err
+	}
+	return f, nil
+}
+
+// Bind makes the framebuffer the target for drawing and reading
+func (f *Framebuffer) Bind() {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, f.ID)
+}
+
+// Resize reallocates the attachments at a new size, discarding their contents
+func (f *Framebuffer) Resize(width, height int) error {
+	var prev int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &prev)
+	defer gl.BindFramebuffer(gl.FRAMEBUFFER, uint32(prev))
+
+	f.Width, f.Height = width, height
+
+	gl.BindTexture(gl.TEXTURE_2D, f.Color)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA8, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+
+	gl.BindRenderbuffer(gl.RENDERBUFFER, f.Depth)
+	gl.RenderbufferStorage(gl.RENDERBUFFER, gl.DEPTH24_STENCIL8, int32(width), int32(height))
+	gl.BindRenderbuffer(gl.RENDERBUFFER, 0)
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, f.ID)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, f.Color, 0)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, f.Depth)
+
+	if status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); status != gl.FRAMEBUFFER_COMPLETE {
+		return fmt.Errorf("framebuffer %dx%d incomplete: 0x%x", width, height, status)
+	}
+	return nil
+}
+
+// Delete releases the framebuffer and its attachments
+func (f *Framebuffer) Delete() {
+	gl.DeleteFramebuffers(1, &f.ID)
+	gl.DeleteTextures(1, &f.Color)
+	gl.DeleteRenderbuffers(1, &f.Depth)
+}
@@ -0,0 +1,255 @@
+package glutil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ShaderDir is where #include paths are looked up when they are not found next
+// to the including file
+var ShaderDir = "shaders"
+
+// Defines are extra macros injected into a shader right after its #version line
+type Defines map[string]string
+
+// LineOrigin is the file and 1-based line an output line of a Source came from
+type LineOrigin struct {
+	File string
+	Line int
+}
+
+// Source is a preprocessed shader. Lines maps every line of Text back to where
+// it was written so driver errors can be reported against the original files.
+type Source struct {
+	Name  string
+	Text  string
+	Files []string
+	Lines []LineOrigin
+}
+
+// Origin maps a 1-based line of the preprocessed text to its original location
+func (s *Source) Origin(line int) (string, int) {
+	if line < 1 || line > len(s.Lines) {
+		return s.Name, line
+	}
+	o := s.Lines[line-1]
+	return o.File, o.Line
+}
+
+type preprocessor struct {
+	out      bytes.Buffer
+	lines    []LineOrigin
+	files    []string
+	seen     map[string]bool
+	once     map[string]bool
+	guards   map[string]string
+	defined  map[string]bool
+	stack    []string
+	defines  Defines
+	injected bool
+}
+
+// Preprocess expands #include directives in a shader source file and injects
+// the given defines after its #version line. Files are read from Assets, so
+// names are slash separated whatever the OS.
+func Preprocess(filename string, defines Defines) (*Source, error) {
+	pp := &preprocessor{
+		seen:    make(map[string]bool),
+		once:    make(map[string]bool),
+		guards:  make(map[string]string),
+		defined: make(map[string]bool),
+		defines: defines,
+	}
+
+	if err := pp.include(path.Clean(filepath.ToSlash(filename))); err != nil {
+		return &Source{Name: filename, Files: pp.files}, err
+	}
+
+	src := &Source{
+		Name:  filename,
+		Text:  pp.out.String(),
+		Files: pp.files,
+		Lines: pp.lines,
+	}
+
+	// A file without a #version line gets its defines at the very top
+	if !pp.injected && len(defines) > 0 {
+		body, lines := pp.out.String(), pp.lines
+		pp.out.Reset()
+		pp.lines = nil
+		pp.emitDefines(filename, 1)
+		src.Text = pp.out.String() + body
+		src.Lines = append(pp.lines, lines...)
+	}
+
+	return src, nil
+}
+
+func (pp *preprocessor) emit(line string, origin LineOrigin) {
+	pp.out.WriteString(line)
+	pp.out.WriteByte('\n')
+	pp.lines = append(pp.lines, origin)
+}
+
+func (pp *preprocessor) emitDefines(file string, line int) {
+	names := make([]string, 0, len(pp.defines))
+	for name := range pp.defines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pp.defined[name] = true
+		pp.emit(strings.TrimSpace("#define "+name+" "+pp.defines[name]), LineOrigin{File: file, Line: line})
+	}
+	pp.injected = true
+}
+
+func (pp *preprocessor) include(filename string) error {
+	for _, open := range pp.stack {
+		if open == filename {
+			return fmt.Errorf("include cycle: %s -> %s", strings.Join(pp.stack, " -> "), filename)
+		}
+	}
+
+	// Skip files that asked to be included once, or whose guard macro is already set
+	if pp.once[filename] {
+		return nil
+	}
+	if guard, ok := pp.guards[filename]; ok && pp.defined[guard] {
+		return nil
+	}
+
+	data, err := ReadAsset(filename)
+	if err != nil {
+		return err
+	}
+
+	if !pp.seen[filename] {
+		pp.seen[filename] = true
+		pp.files = append(pp.files, filename)
+	}
+
+	if guard := includeGuard(data); guard != "" {
+		pp.guards[filename] = guard
+	}
+
+	pp.stack = append(pp.stack, filename)
+	defer func() { pp.stack = pp.stack[:len(pp.stack)-1] }()
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		origin := LineOrigin{File: filename, Line: lineNo}
+
+		directive, arg := splitDirective(line)
+		switch directive {
+		case "version":
+			if len(pp.stack) > 1 {
+				return fmt.Errorf("%s:%d: #version in included file", filename, lineNo)
+			}
+			pp.emit(line, origin)
+			pp.emitDefines(filename, lineNo)
+
+		case "include":
+			name, err := includeName(arg)
+			if err != nil {
+				return fmt.Errorf("%s:%d: %v", filename, lineNo, err)
+			}
+			included, err := resolveInclude(filename, name)
+			if err != nil {
+				return fmt.Errorf("%s:%d: %v", filename, lineNo, err)
+			}
+			if err := pp.include(included); err != nil {
+				return err
+			}
+
+		case "pragma":
+			if strings.TrimSpace(arg) == "once" {
+				pp.once[filename] = true
+				continue
+			}
+			pp.emit(line, origin)
+
+		case "define":
+			if fields := strings.Fields(arg); len(fields) > 0 {
+				pp.defined[fields[0]] = true
+			}
+			pp.emit(line, origin)
+
+		default:
+			pp.emit(line, origin)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// splitDirective returns the name and argument of a preprocessor directive line
+func splitDirective(line string) (string, string) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "#") {
+		return "", ""
+	}
+	trimmed = strings.TrimSpace(trimmed[1:])
+
+	end := strings.IndexAny(trimmed, " \t")
+	if end < 0 {
+		return trimmed, ""
+	}
+	return trimmed[:end], strings.TrimSpace(trimmed[end:])
+}
+
+func includeName(arg string) (string, error) {
+	if len(arg) < 2 || arg[0] != '"' || arg[len(arg)-1] != '"' {
+		return "", fmt.Errorf("malformed #include %s", arg)
+	}
+	return arg[1 : len(arg)-1], nil
+}
+
+// resolveInclude looks for an include next to the including file, then in ShaderDir
+func resolveInclude(from, name string) (string, error) {
+	candidates := []string{
+		path.Join(path.Dir(from), name),
+		path.Join(ShaderDir, name),
+	}
+	for _, candidate := range candidates {
+		if _, err := StatAsset(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("cannot find include %q", name)
+}
+
+// includeGuard recognises the classic #ifndef X / #define X ... #endif wrapper and
+// returns X, so that a second include of the file can be skipped outright
+func includeGuard(data []byte) string {
+	var directives [][2]string
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		name, arg := splitDirective(trimmed)
+		directives = append(directives, [2]string{name, arg})
+	}
+
+	if len(directives) < 3 {
+		return ""
+	}
+	first, second, last := directives[0], directives[1], directives[len(directives)-1]
+	if first[0] != "ifndef" || second[0] != "define" || last[0] != "endif" {
+		return ""
+	}
+	if fields := strings.Fields(second[1]); len(fields) == 0 || fields[0] != first[1] {
+		return ""
+	}
+	return first[1]
+}
@@ -0,0 +1,167 @@
+package glutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// withAssets swaps Assets for the length of a test
+func withAssets(t *testing.T, files map[string]string) {
+	t.Helper()
+	fsys := make(fstest.MapFS)
+	for name, data := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(data)}
+	}
+	prev := Assets
+	Assets = fsys
+	t.Cleanup(func() { Assets = prev })
+}
+
+func TestPreprocess(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		entry   string
+		defines Defines
+
+		// Expected output text, files read and origin of every output line
+		text    string
+		read    []string
+		origins []string
+		err     string
+	}{
+		{
+			name:    "defines after version",
+			files:   map[string]string{"shaders/a.glsl": "#version 410 core\nvoid main() {}\n"},
+			entry:   "shaders/a.glsl",
+			defines: Defines{"B": "", "A": "1"},
+			text:    "#version 410 core\n#define A 1\n#define B\nvoid main() {}\n",
+			read:    []string{"shaders/a.glsl"},
+			origins: []string{"shaders/a.glsl:1", "shaders/a.glsl:1", "shaders/a.glsl:1", "shaders/a.glsl:2"},
+		},
+		{
+			name:    "defines at top without version",
+			files:   map[string]string{"a.glsl": "float x;\n"},
+			entry:   "a.glsl",
+			defines: Defines{"A": "1"},
+			text:    "#define A 1\nfloat x;\n",
+			read:    []string{"a.glsl"},
+			origins: []string{"a.glsl:1", "a.glsl:1"},
+		},
+		{
+			name: "include next to file and from ShaderDir",
+			files: map[string]string{
+				"scene/a.glsl":        "#version 410 core\n#include \"local.glsl\"\n#include \"common.glsl\"\nend\n",
+				"scene/local.glsl":    "local\n",
+				"shaders/common.glsl": "common1\ncommon2\n",
+			},
+			entry:   "scene/a.glsl",
+			text:    "#version 410 core\nlocal\ncommon1\ncommon2\nend\n",
+			read:    []string{"scene/a.glsl", "scene/local.glsl", "shaders/common.glsl"},
+			origins: []string{"scene/a.glsl:1", "scene/local.glsl:1", "shaders/common.glsl:1", "shaders/common.glsl:2", "scene/a.glsl:4"},
+		},
+		{
+			name: "include guard skips second include",
+			files: map[string]string{
+				"a.glsl": "#include \"g.glsl\"\n#include \"g.glsl\"\nend\n",
+				"g.glsl": "// guarded\n#ifndef G\n#define G\nbody\n#endif\n",
+			},
+			entry:   "a.glsl",
+			text:    "// guarded\n#ifndef G\n#define G\nbody\n#endif\nend\n",
+			read:    []string{"a.glsl", "g.glsl"},
+			origins: []string{"g.glsl:1", "g.glsl:2", "g.glsl:3", "g.glsl:4", "g.glsl:5", "a.glsl:3"},
+		},
+		{
+			name: "pragma once",
+			files: map[string]string{
+				"a.glsl": "#include \"o.glsl\"\n#include \"o.glsl\"\n",
+				"o.glsl": "#pragma once\nonce\n",
+			},
+			entry:   "a.glsl",
+			text:    "once\n",
+			read:    []string{"a.glsl", "o.glsl"},
+			origins: []string{"o.glsl:2"},
+		},
+		{
+			name:  "unclean entry name",
+			files: map[string]string{"shaders/a.glsl": "x\n"},
+			entry: "shaders/../shaders/./a.glsl",
+			text:  "x\n", read: []string{"shaders/a.glsl"},
+			origins: []string{"shaders/a.glsl:1"},
+		},
+		{
+			name: "include cycle",
+			files: map[string]string{
+				"a.glsl": "#include \"b.glsl\"\n",
+				"b.glsl": "#include \"a.glsl\"\n",
+			},
+			entry: "a.glsl",
+			err:   "include cycle: a.glsl -> b.glsl -> a.glsl",
+		},
+		{
+			name:  "missing include",
+			files: map[string]string{"a.glsl": "\n#include \"nope.glsl\"\n"},
+			entry: "a.glsl",
+			err:   `a.glsl:2: cannot find include "nope.glsl"`,
+		},
+		{
+			name:  "malformed include",
+			files: map[string]string{"a.glsl": "#include <b.glsl>\n"},
+			entry: "a.glsl",
+			err:   "a.glsl:1: malformed #include <b.glsl>",
+		},
+		{
+			name: "version in include",
+			files: map[string]string{
+				"a.glsl": "#version 410 core\n#include \"b.glsl\"\n",
+				"b.glsl": "#version 410 core\n",
+			},
+			entry: "a.glsl",
+			err:   "b.glsl:1: #version in included file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withAssets(t, tt.files)
+			src, err := Preprocess(tt.entry, tt.defines)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if src.Text != tt.text {
+				t.Errorf("text:\n%s\nwant:\n%s", src.Text, tt.text)
+			}
+			if !reflect.DeepEqual(src.Files, tt.read) {
+				t.Errorf("files %v, want %v", src.Files, tt.read)
+			}
+			var origins []string
+			for i := range src.Lines {
+				file, line := src.Origin(i + 1)
+				origins = append(origins, fmt.Sprintf("%s:%d", file, line))
+			}
+			if !reflect.DeepEqual(origins, tt.origins) {
+				t.Errorf("origins %v, want %v", origins, tt.origins)
+			}
+		})
+	}
+}
+
+func TestSourceOriginOutOfRange(t *testing.T) {
+	src := &Source{Name: "a.glsl", Lines: []LineOrigin{{File: "b.glsl", Line: 7}}}
+	if file, line := src.Origin(1); file != "b.glsl" || line != 7 {
+		t.Errorf("Origin(1) = %s:%d, want b.glsl:7", file, line)
+	}
+	if file, line := src.Origin(5); file != "a.glsl" || line != 5 {
+		t.Errorf("Origin(5) = %s:%d, want a.glsl:5", file, line)
+	}
+}
@@ -0,0 +1,77 @@
+package glutil
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// ReadPixels reads a width x height rectangle from the bottom left of the
+// current read framebuffer. GL returns rows bottom first, so they are flipped
+// to give a normal top-down image.
+func ReadPixels(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	if width <= 0 || height <= 0 {
+		return img
+	}
+
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+
+	row := make([]byte, img.Stride)
+	for y := 0; y < height/2; y++ {
+		top := img.Pix[y*img.Stride : (y+1)*img.Stride]
+		bottom := img.Pix[(height-1-y)*img.Stride : (height-y)*img.Stride]
+		copy(row, top)
+		copy(top, bottom)
+		copy(bottom, row)
+	}
+	return img
+}
+
+// SavePNG writes an image to a PNG file
+func SavePNG(filename string, img image.Image) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadFramebuffer reads a width x height rectangle from framebuffer fb, where
+// 0 is the window's. The previous read framebuffer is bound again afterwards.
+func ReadFramebuffer(fb uint32, width, height int) *image.RGBA {
+	var prev int32
+	gl.GetIntegerv(gl.READ_FRAMEBUFFER_BINDING, &prev)
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, fb)
+	defer gl.BindFramebuffer(gl.READ_FRAMEBUFFER, uint32(prev))
+
+	return ReadPixels(width, height)
+}
+
+// ScreenshotDir is where Screenshot writes its files
+var ScreenshotDir = "screenshots"
+
+// Screenshot saves what has been drawn to the current read framebuffer so far
+// this frame as a timestamped PNG in ScreenshotDir, returning the file name.
+// Call it after drawing and before swapping buffers.
+func Screenshot(v *Viewport) (string, error) {
+	if err := os.MkdirAll(ScreenshotDir, 0755); err != nil {
+		return "", err
+	}
+
+	name := filepath.Join(ScreenshotDir, "screenshot-"+time.Now().Format("20060102-150405.000")+".png")
+	if err := SavePNG(name, ReadPixels(v.Width, v.Height)); err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -0,0 +1,119 @@
+package glutil
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Recorder captures every frame drawn while it is open. Frames are meant to
+// be produced on a fixed simulated timestep of Step seconds rather than the
+// wall clock, so a recording plays back at the same speed however slowly it
+// was rendered.
+type Recorder struct {
+	Path   string
+	FPS    int
+	Step   float64
+	Frames int
+
+	// Y4M output; nil when writing a PNG sequence. The header, and with it
+	// the size of every frame, is written with the first frame.
+	file   *os.File
+	y4m    *bufio.Writer
+	header bool
+	width  int
+	height int
+}
+
+// NewRecorder starts a recording at fps frames per second. A path ending in
+// .y4m is written as an uncompressed YUV4MPEG2 stream, which ffmpeg reads
+// directly; anything else is a directory of numbered PNGs.
+func NewRecorder(path string, fps int) (*Recorder, error) {
+	if fps <= 0 {
+		return nil, fmt.Errorf("recording needs a positive frame rate, got %d", fps)
+	}
+	r := &Recorder{Path: path, FPS: fps, Step: 1 / float64(fps)}
+
+	if strings.ToLower(filepath.Ext(path)) == ".y4m" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		r.file = f
+		r.y4m = bufio.NewWriter(f)
+	} else if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Capture reads the frame drawn so far from the current read framebuffer and
+// appends it to the recording. Call it after drawing and before swapping.
+// Frames that fail to be written are not counted.
+func (r *Recorder) Capture(v *Viewport) error {
+	img := ReadPixels(v.Width, v.Height)
+
+	if r.y4m == nil {
+		if err := SavePNG(filepath.Join(r.Path, fmt.Sprintf("frame-%05d.png", r.Frames+1)), img); err != nil {
+			return err
+		}
+		r.Frames++
+		return nil
+	}
+
+	// A Y4M stream has one size for every frame, fixed by the first
+	if !r.header {
+		r.width, r.height = v.Width, v.Height
+		if _, err := fmt.Fprintf(r.y4m, "YUV4MPEG2 W%d H%d F%d:1 Ip A1:1 C444\n", r.width, r.height, r.FPS); err != nil {
+			return err
+		}
+		r.header = true
+	} else if v.Width != r.width || v.Height != r.height {
+		return fmt.Errorf("%s: frame size changed from %dx%d to %dx%d", r.Path, r.width, r.height, v.Width, v.Height)
+	}
+	if err := r.writeY4MFrame(img); err != nil {
+		return err
+	}
+	r.Frames++
+	return nil
+}
+
+// writeY4MFrame writes full resolution Y, Cb and Cr planes using the BT.601
+// video range ffmpeg assumes for Y4M input
+func (r *Recorder) writeY4MFrame(img *image.RGBA) error {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	planes := make([]byte, 3*w*h)
+	y, cb, cr := planes[:w*h], planes[w*h:2*w*h], planes[2*w*h:]
+
+	for i := 0; i < w*h; i++ {
+		p := img.Pix[4*i : 4*i+3]
+		R, G, B := float64(p[0]), float64(p[1]), float64(p[2])
+		y[i] = uint8(16.5 + (65.481*R+128.553*G+24.966*B)/255)
+		cb[i] = uint8(128.5 + (-37.797*R-74.203*G+112.0*B)/255)
+		cr[i] = uint8(128.5 + (112.0*R-93.786*G-18.214*B)/255)
+	}
+
+	if _, err := r.y4m.WriteString("FRAME\n"); err != nil {
+		return err
+	}
+	_, err := r.y4m.Write(planes)
+	return err
+}
+
+// Close finishes the recording
+func (r *Recorder) Close() error {
+	if r.y4m == nil {
+		return nil
+	}
+	if err := r.y4m.Flush(); err != nil {
+		r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
@@ -0,0 +1,123 @@
+package glutil
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// ReloadInterval is how often Poll looks at the shader sources in Assets.
+// Only sources on disk change; embedded ones never trigger a reload.
+var ReloadInterval = 500 * time.Millisecond
+
+type watchState struct {
+	sources   map[string]time.Time
+	lastCheck time.Time
+}
+
+func newWatchState(sources map[string]time.Time) *watchState {
+	return &watchState{
+		sources:   sources,
+		lastCheck: time.Now(),
+	}
+}
+
+// changed reports whether any of the watched files has a different modification
+// time from the one recorded at the last build
+func (w *watchState) changed(current map[string]time.Time) bool {
+	if len(current) != len(w.sources) {
+		return true
+	}
+	for name, modTime := range current {
+		if prev, ok := w.sources[name]; !ok || !prev.Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceTimes stats every file the program is built from. Files that cannot be
+// stat'd (e.g. mid-save by an editor) are left out so they register as a change
+// once they reappear.
+func (p *Program) sourceTimes() map[string]time.Time {
+	times := make(map[string]time.Time)
+	for _, name := range p.files {
+		info, err := StatAsset(name)
+		if err != nil {
+			continue
+		}
+		times[name] = info.ModTime()
+	}
+	return times
+}
+
+// Reload recompiles the program if any of its sources changed on disk. If the new
+// sources fail to compile or link, the last good program stays in place and the
+// error is returned. The boolean result reports whether the program was replaced,
+// in which case uniform values must be set again.
+func (p *Program) Reload() (bool, error) {
+	current := p.sourceTimes()
+	if !p.watch.changed(current) {
+		return false, nil
+	}
+
+	// Record the new times even on failure so a broken file is only reported once
+	p.watch.sources = current
+
+	id, files, err := compileProgram(p.vertexShaderName, p.fragmentShaderName, p.defines)
+	if err != nil {
+		// Keep watching anything newly included so fixing it triggers a rebuild
+		p.files = mergeFiles(p.files, files)
+		p.watch.sources = p.sourceTimes()
+		return false, err
+	}
+	p.files = files
+	p.watch.sources = p.sourceTimes()
+
+	// If the old program was bound, bind its replacement in its place
+	var bound int32
+	gl.GetIntegerv(gl.CURRENT_PROGRAM, &bound)
+
+	gl.DeleteProgram(p.ID)
+	wasBound := uint32(bound) == p.ID
+	p.ID = id
+	p.reflect()
+
+	if wasBound {
+		p.Use()
+	}
+
+	fmt.Printf("Reloaded program %s / %s\n", p.vertexShaderName, p.fragmentShaderName)
+	return true, nil
+}
+
+// Poll calls Reload at most once every ReloadInterval, printing any compile or
+// link failure. It returns true when the program was relinked.
+func (p *Program) Poll() bool {
+	now := time.Now()
+	if now.Sub(p.watch.lastCheck) < ReloadInterval {
+		return false
+	}
+	p.watch.lastCheck = now
+
+	reloaded, err := p.Reload()
+	if shaderErr, ok := err.(*ShaderError); ok {
+		fmt.Print(shaderErr.Pretty())
+	} else if err != nil {
+		fmt.Printf("%+v\n", err)
+	}
+	return reloaded
+}
+
+func mergeFiles(a, b []string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, name := range append(append([]string{}, a...), b...) {
+		if !seen[name] {
+			seen[name] = true
+			merged = append(merged, name)
+		}
+	}
+	return merged
+}
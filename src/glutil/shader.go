@@ -0,0 +1,156 @@
+// Package glutil holds the GL helpers shared by the tutorial programs
+package glutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Program is a linked shader program and the source files it was built from
+type Program struct {
+	ID uint32
+
+	vertexShaderName   string
+	fragmentShaderName string
+	defines            Defines
+
+	// files is every source that went into the last build, includes and all
+	files      []string
+	uniforms   map[string]Uniform
+	attributes map[string]Attribute
+	warned     map[string]bool
+	watch      *watchState
+}
+
+// NewProgram compiles and links a program from a vertex and fragment shader
+func NewProgram(vertexShaderName string, fragmentShaderName string) (*Program, error) {
+	return NewProgramDefines(vertexShaderName, fragmentShaderName, nil)
+}
+
+// NewProgramDefines is NewProgram with extra macros injected into both shaders,
+// for building variants of the same sources
+func NewProgramDefines(vertexShaderName string, fragmentShaderName string, defines Defines) (*Program, error) {
+	id, files, err := compileProgram(vertexShaderName, fragmentShaderName, defines)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Program{
+		ID:                 id,
+		vertexShaderName:   vertexShaderName,
+		fragmentShaderName: fragmentShaderName,
+		defines:            defines,
+		files:              files,
+	}
+	p.reflect()
+	p.watch = newWatchState(p.sourceTimes())
+	return p, nil
+}
+
+// Use makes the program part of the current rendering state
+func (p *Program) Use() {
+	gl.UseProgram(p.ID)
+}
+
+// Delete releases the underlying GL program
+func (p *Program) Delete() {
+	gl.DeleteProgram(p.ID)
+	p.ID = 0
+}
+
+// CompileProgram compiles both shaders and links them, returning the GL program name
+func CompileProgram(vertexShaderName string, fragmentShaderName string) (uint32, error) {
+	program, _, err := compileProgram(vertexShaderName, fragmentShaderName, nil)
+	return program, err
+}
+
+// compileProgram builds a program and also returns every file that was read
+// while preprocessing, even if the build failed
+func compileProgram(vertexShaderName string, fragmentShaderName string, defines Defines) (uint32, []string, error) {
+	vertexShader, vertexFiles, err := compileShaderFile(vertexShaderName, gl.VERTEX_SHADER, defines)
+	if err != nil {
+		return 0, vertexFiles, err
+	}
+	defer gl.DeleteShader(vertexShader)
+
+	fragShader, fragFiles, err := compileShaderFile(fragmentShaderName, gl.FRAGMENT_SHADER, defines)
+	files := mergeFiles(vertexFiles, fragFiles)
+	if err != nil {
+		return 0, files, err
+	}
+	defer gl.DeleteShader(fragShader)
+
+	program := gl.CreateProgram()
+	gl.AttachShader(program, vertexShader)
+	gl.AttachShader(program, fragShader)
+	gl.LinkProgram(program)
+
+	var status int32
+	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
+	if status != gl.TRUE {
+		var logLength int32
+		gl.GetProgramiv(program, gl.INFO_LOG_LENGTH, &logLength)
+
+		log := strings.Repeat("\x00", int(logLength)+1)
+		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
+		gl.DeleteProgram(program)
+
+		return 0, files, fmt.Errorf("Failed to compile program %s / %s: %v", vertexShaderName, fragmentShaderName, log)
+	}
+
+	return program, files, nil
+}
+
+// CompileShader preprocesses a shader source file and compiles it as the given shader type
+func CompileShader(sourceFilename string, shaderType uint32) (uint32, error) {
+	shader, _, err := compileShaderFile(sourceFilename, shaderType, nil)
+	return shader, err
+}
+
+// CompileShaderDefines is CompileShader with extra macros injected after #version
+func CompileShaderDefines(sourceFilename string, shaderType uint32, defines Defines) (uint32, error) {
+	shader, _, err := compileShaderFile(sourceFilename, shaderType, defines)
+	return shader, err
+}
+
+func compileShaderFile(sourceFilename string, shaderType uint32, defines Defines) (uint32, []string, error) {
+	// Expand includes and defines into a single source string
+	src, err := Preprocess(sourceFilename, defines)
+	if err != nil {
+		return 0, src.Files, err
+	}
+
+	shader, err := compileSource(src, shaderType)
+	return shader, src.Files, err
+}
+
+func compileSource(src *Source, shaderType uint32) (uint32, error) {
+	// Convert the source to format suitable for loading into OpenGL
+	shaderBytesLen := int32(len(src.Text))
+	shaderStr, shaderStrFree := gl.Strs(src.Text)
+
+	// Initialize a shader
+	shader := gl.CreateShader(shaderType)
+	gl.ShaderSource(shader, 1, shaderStr, &shaderBytesLen)
+	shaderStrFree()
+	gl.CompileShader(shader)
+
+	// Check for errors
+	var status int32
+	gl.GetShaderiv(shader, gl.COMPILE_STATUS, &status)
+	if status != gl.TRUE {
+		var logLength int32
+		gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &logLength)
+
+		log := strings.Repeat("\x00", int(logLength)+1)
+		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
+		gl.DeleteShader(shader)
+
+		return 0, newShaderError(src, shaderType, log)
+	}
+
+	fmt.Printf("Loaded shader: %s\n", src.Name)
+	return shader, nil
+}
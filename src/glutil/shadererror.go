@@ -0,0 +1,233 @@
+package glutil
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Severity of a shader compiler diagnostic
+type Severity uint8
+
+// Severity consts
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// Diagnostic is a single message from a driver's shader info log. File and Line
+// refer to the original source, not the preprocessed text handed to the driver.
+// Line and Column are 0 when the driver did not report them.
+type Diagnostic struct {
+	File     string
+	Line     int
+	Column   int
+	Severity Severity
+	Message  string
+
+	// textLine is the line in the preprocessed text, used to quote the source
+	textLine int
+}
+
+func (d Diagnostic) String() string {
+	switch {
+	case d.Line == 0:
+		return fmt.Sprintf("%s: %s: %s", d.File, d.Severity, d.Message)
+	case d.Column == 0:
+		return fmt.Sprintf("%s:%d: %s: %s", d.File, d.Line, d.Severity, d.Message)
+	default:
+		return fmt.Sprintf("%s:%d:%d: %s: %s", d.File, d.Line, d.Column, d.Severity, d.Message)
+	}
+}
+
+// ShaderError is returned when a shader fails to compile
+type ShaderError struct {
+	Stage       string
+	File        string
+	Log         string
+	Diagnostics []Diagnostic
+
+	source *Source
+}
+
+func (e *ShaderError) Error() string {
+	if len(e.Diagnostics) == 0 {
+		return fmt.Sprintf("Failed to compile %s shader %s: %v", e.Stage, e.File, e.Log)
+	}
+
+	msgs := make([]string, len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		msgs[i] = d.String()
+	}
+	return fmt.Sprintf("Failed to compile %s shader %s:\n%s", e.Stage, e.File, strings.Join(msgs, "\n"))
+}
+
+// Pretty renders every diagnostic followed by the offending source line and a
+// caret under the reported column, or under the start of the line when the
+// driver gives no column
+func (e *ShaderError) Pretty() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Failed to compile %s shader %s\n", e.Stage, e.File)
+	if len(e.Diagnostics) == 0 {
+		buf.WriteString(e.Log)
+		buf.WriteByte('\n')
+		return buf.String()
+	}
+
+	var lines []string
+	if e.source != nil {
+		lines = strings.Split(e.source.Text, "\n")
+	}
+
+	for _, d := range e.Diagnostics {
+		buf.WriteString(d.String())
+		buf.WriteByte('\n')
+
+		if d.textLine < 1 || d.textLine > len(lines) {
+			continue
+		}
+		text := strings.Replace(lines[d.textLine-1], "\t", "    ", -1)
+		gutter := fmt.Sprintf("%5d | ", d.Line)
+		buf.WriteString(gutter)
+		buf.WriteString(text)
+		buf.WriteByte('\n')
+
+		col := d.Column
+		if col < 1 {
+			col = len(text) - len(strings.TrimLeft(text, " ")) + 1
+		}
+		if col > len(text)+1 {
+			col = len(text) + 1
+		}
+		buf.WriteString(strings.Repeat(" ", len(gutter)-2) + "| ")
+		buf.WriteString(strings.Repeat(" ", col-1))
+		buf.WriteString("^\n")
+	}
+	return buf.String()
+}
+
+// Log formats understood by ParseShaderLog:
+//
+//	Mesa:         0:12(5): error: syntax error, unexpected ...
+//	NVIDIA:       0(12) : error C0000: syntax error, unexpected ...
+//	AMD/Intel:    ERROR: 0:12: 'foo' : undeclared identifier
+var (
+	mesaLogLine   = regexp.MustCompile(`^\d+:(\d+)\((\d+)\):\s*(error|warning|info)\w*:\s*(.*)$`)
+	nvidiaLogLine = regexp.MustCompile(`^\d+\((\d+)\)\s*:\s*(error|warning|info)\w*\s*(?:[A-Z]\d+)?\s*:\s*(.*)$`)
+	amdLogLine    = regexp.MustCompile(`^(ERROR|WARNING|INFO):\s*\d+:(\d+):\s*(.*)$`)
+	amdSummary    = regexp.MustCompile(`^(ERROR|WARNING): \d+ compilation (errors|warnings)`)
+)
+
+// ParseShaderLog splits a driver info log into diagnostics. Line numbers are those
+// reported by the driver; lines that match no known format are kept as messages
+// without a location.
+func ParseShaderLog(log string) []Diagnostic {
+	var diags []Diagnostic
+	for _, line := range strings.Split(strings.TrimRight(log, "\x00"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || amdSummary.MatchString(line) {
+			continue
+		}
+
+		if m := mesaLogLine.FindStringSubmatch(line); m != nil {
+			diags = append(diags, Diagnostic{
+				Line:     atoi(m[1]),
+				Column:   atoi(m[2]),
+				Severity: parseSeverity(m[3]),
+				Message:  m[4],
+			})
+		} else if m := nvidiaLogLine.FindStringSubmatch(line); m != nil {
+			diags = append(diags, Diagnostic{
+				Line:     atoi(m[1]),
+				Severity: parseSeverity(m[2]),
+				Message:  m[3],
+			})
+		} else if m := amdLogLine.FindStringSubmatch(line); m != nil {
+			diags = append(diags, Diagnostic{
+				Line:     atoi(m[2]),
+				Severity: parseSeverity(m[1]),
+				Message:  m[3],
+			})
+		} else {
+			diags = append(diags, Diagnostic{
+				Severity: SeverityError,
+				Message:  line,
+			})
+		}
+	}
+	return diags
+}
+
+// newShaderError parses a driver log and maps each diagnostic back to the
+// original file and line through the preprocessor's line table
+func newShaderError(src *Source, shaderType uint32, log string) *ShaderError {
+	log = strings.TrimSpace(strings.TrimRight(log, "\x00"))
+
+	diags := ParseShaderLog(log)
+	for i := range diags {
+		d := &diags[i]
+		d.textLine = d.Line
+		if d.Line > 0 {
+			d.File, d.Line = src.Origin(d.Line)
+		} else {
+			d.File = src.Name
+		}
+	}
+
+	return &ShaderError{
+		Stage:       shaderStage(shaderType),
+		File:        src.Name,
+		Log:         log,
+		Diagnostics: diags,
+		source:      src,
+	}
+}
+
+func shaderStage(shaderType uint32) string {
+	switch shaderType {
+	case gl.VERTEX_SHADER:
+		return "vertex"
+	case gl.FRAGMENT_SHADER:
+		return "fragment"
+	case gl.GEOMETRY_SHADER:
+		return "geometry"
+	case gl.TESS_CONTROL_SHADER:
+		return "tessellation control"
+	case gl.TESS_EVALUATION_SHADER:
+		return "tessellation evaluation"
+	default:
+		return "unknown"
+	}
+}
+
+func parseSeverity(s string) Severity {
+	switch strings.ToLower(s) {
+	case "warning":
+		return SeverityWarning
+	case "info":
+		return SeverityInfo
+	default:
+		return SeverityError
+	}
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,111 @@
+package glutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+func TestParseShaderLog(t *testing.T) {
+	tests := []struct {
+		name string
+		log  string
+		want []Diagnostic
+	}{
+		{
+			name: "mesa",
+			log:  "0:3(18): error: `foo' undeclared\n",
+			want: []Diagnostic{{Line: 3, Column: 18, Severity: SeverityError, Message: "`foo' undeclared"}},
+		},
+		{
+			name: "nvidia",
+			log:  "0(3) : error C1008: undefined variable \"foo\"\n\x00",
+			want: []Diagnostic{{Line: 3, Severity: SeverityError, Message: `undefined variable "foo"`}},
+		},
+		{
+			name: "amd",
+			log:  "ERROR: 0:3: 'foo' : undeclared identifier \nERROR: 1 compilation errors.  No code generated.\n",
+			want: []Diagnostic{{Line: 3, Severity: SeverityError, Message: "'foo' : undeclared identifier"}},
+		},
+		{
+			name: "warning",
+			log:  "0:7(1): warning: extension `GL_foo' unsupported",
+			want: []Diagnostic{{Line: 7, Column: 1, Severity: SeverityWarning, Message: "extension `GL_foo' unsupported"}},
+		},
+		{
+			name: "unknown format",
+			log:  "something went wrong",
+			want: []Diagnostic{{Severity: SeverityError, Message: "something went wrong"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseShaderLog(tt.log)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d diagnostics %v, want %d", len(got), got, len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("diagnostic %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestShaderErrorPretty(t *testing.T) {
+	// Line 3 of the text handed to the driver is line 2 of the original file
+	src := &Source{
+		Name: "shaders/a.glsl",
+		Text: "#version 410 core\n#define X 1\n\tcolor = vec4(foo);\n",
+		Lines: []LineOrigin{
+			{File: "shaders/a.glsl", Line: 1},
+			{File: "shaders/a.glsl", Line: 1},
+			{File: "shaders/a.glsl", Line: 2},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		log   string
+		first string
+		caret string
+	}{
+		{
+			name:  "mesa column",
+			log:   "0:3(18): error: `foo' undeclared",
+			first: "shaders/a.glsl:2:18: error: `foo' undeclared",
+			caret: "      |                  ^",
+		},
+		{
+			name:  "nvidia no column",
+			log:   "0(3) : error C1008: undefined variable \"foo\"",
+			first: `shaders/a.glsl:2: error: undefined variable "foo"`,
+			caret: "      |     ^",
+		},
+		{
+			name:  "amd no column",
+			log:   "ERROR: 0:3: 'foo' : undeclared identifier",
+			first: "shaders/a.glsl:2: error: 'foo' : undeclared identifier",
+			caret: "      |     ^",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newShaderError(src, gl.FRAGMENT_SHADER, tt.log)
+			lines := strings.Split(strings.TrimSuffix(err.Pretty(), "\n"), "\n")
+			want := []string{
+				"Failed to compile fragment shader shaders/a.glsl",
+				tt.first,
+				"    2 |     color = vec4(foo);",
+				tt.caret,
+			}
+			if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+				t.Errorf("Pretty:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+			}
+		})
+	}
+}
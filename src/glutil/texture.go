@@ -0,0 +1,65 @@
+package glutil
+
+import (
+	"fmt"
+	"io"
+
+	"image"
+
+	_ "image/jpeg"
+	_ "image/png"
+
+	"image/draw"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// LoadImage decodes a PNG or JPEG file from Assets into RGBA pixels
+func LoadImage(filename string) (*image.RGBA, error) {
+	imFile, err := OpenAsset(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image %s: %v", filename, err)
+	}
+	defer imFile.Close()
+
+	return DecodeImage(imFile, filename)
+}
+
+// DecodeImage decodes PNG or JPEG data into RGBA pixels; name is only used in messages
+func DecodeImage(r io.Reader, name string) (*image.RGBA, error) {
+	im, filetype, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %s: %+v", name, err)
+	}
+	fmt.Printf("Loaded %s as %s!\n", name, filetype)
+	switch actualIm := im.(type) {
+	case *image.RGBA:
+		return actualIm, nil
+	default:
+		imCopy := image.NewRGBA(actualIm.Bounds())
+		draw.Draw(imCopy, actualIm.Bounds(), actualIm, image.Pt(0, 0), draw.Src)
+		return imCopy, nil
+	}
+}
+
+// LoadTexture loads an image file into a new 2D texture
+func LoadTexture(filename string) (uint32, error) {
+	img, err := LoadImage(filename)
+	if err != nil {
+		return 0, err
+	}
+
+	return NewTexture(img), nil
+}
+
+// NewTexture uploads RGBA pixels into a new 2D texture
+func NewTexture(img *image.RGBA) uint32 {
+	var texture uint32
+	gl.GenTextures(1, &texture)
+	gl.BindTexture(gl.TEXTURE_2D, texture)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(img.Bounds().Dx()), int32(img.Bounds().Dy()),
+		0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+	gl.BindTexture(gl.TEXTURE_2D, 0)
+	return texture
+}
@@ -0,0 +1,194 @@
+package glutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Uniform is an active uniform reported by the driver at link time
+type Uniform struct {
+	Name     string
+	Location int32
+	Type     uint32
+	Size     int32
+}
+
+// Attribute is an active vertex attribute reported by the driver at link time
+type Attribute struct {
+	Name     string
+	Location int32
+	Type     uint32
+	Size     int32
+}
+
+// reflect enumerates the active uniforms and attributes of the linked program
+func (p *Program) reflect() {
+	p.uniforms = make(map[string]Uniform)
+	p.attributes = make(map[string]Attribute)
+	p.warned = make(map[string]bool)
+
+	var count, maxLen int32
+	gl.GetProgramiv(p.ID, gl.ACTIVE_UNIFORMS, &count)
+	gl.GetProgramiv(p.ID, gl.ACTIVE_UNIFORM_MAX_LENGTH, &maxLen)
+	for i := uint32(0); i < uint32(count); i++ {
+		var length, size int32
+		var xtype uint32
+		name := make([]uint8, maxLen+1)
+		gl.GetActiveUniform(p.ID, i, maxLen+1, &length, &size, &xtype, &name[0])
+
+		u := Uniform{Name: string(name[:length]), Type: xtype, Size: size}
+		u.Location = gl.GetUniformLocation(p.ID, gl.Str(u.Name+"\x00"))
+
+		// Arrays are reported as "name[0]"; make them reachable by the bare name too
+		p.uniforms[u.Name] = u
+		if strings.HasSuffix(u.Name, "[0]") {
+			p.uniforms[strings.TrimSuffix(u.Name, "[0]")] = u
+		}
+	}
+
+	gl.GetProgramiv(p.ID, gl.ACTIVE_ATTRIBUTES, &count)
+	gl.GetProgramiv(p.ID, gl.ACTIVE_ATTRIBUTE_MAX_LENGTH, &maxLen)
+	for i := uint32(0); i < uint32(count); i++ {
+		var length, size int32
+		var xtype uint32
+		name := make([]uint8, maxLen+1)
+		gl.GetActiveAttrib(p.ID, i, maxLen+1, &length, &size, &xtype, &name[0])
+
+		a := Attribute{Name: string(name[:length]), Type: xtype, Size: size}
+		a.Location = gl.GetAttribLocation(p.ID, gl.Str(a.Name+"\x00"))
+		p.attributes[a.Name] = a
+	}
+}
+
+// Uniforms returns the active uniforms found when the program was last linked
+func (p *Program) Uniforms() map[string]Uniform {
+	return p.uniforms
+}
+
+// Attributes returns the active vertex attributes found when the program was last linked
+func (p *Program) Attributes() map[string]Attribute {
+	return p.attributes
+}
+
+// UniformLocation returns the location of the named uniform, or -1 if the
+// program has no active uniform by that name
+func (p *Program) UniformLocation(name string) int32 {
+	if u, ok := p.uniforms[name]; ok {
+		return u.Location
+	}
+	return -1
+}
+
+// SetMat4 sets a mat4 uniform
+func (p *Program) SetMat4(name string, m mgl32.Mat4) {
+	if u, ok := p.uniform(name, gl.FLOAT_MAT4); ok {
+		gl.ProgramUniformMatrix4fv(p.ID, u.Location, 1, false, &m[0])
+	}
+}
+
+// SetVec2 sets a vec2 uniform
+func (p *Program) SetVec2(name string, v mgl32.Vec2) {
+	if u, ok := p.uniform(name, gl.FLOAT_VEC2); ok {
+		gl.ProgramUniform2fv(p.ID, u.Location, 1, &v[0])
+	}
+}
+
+// SetVec3 sets a vec3 uniform
+func (p *Program) SetVec3(name string, v mgl32.Vec3) {
+	if u, ok := p.uniform(name, gl.FLOAT_VEC3); ok {
+		gl.ProgramUniform3fv(p.ID, u.Location, 1, &v[0])
+	}
+}
+
+// SetVec4 sets a vec4 uniform
+func (p *Program) SetVec4(name string, v mgl32.Vec4) {
+	if u, ok := p.uniform(name, gl.FLOAT_VEC4); ok {
+		gl.ProgramUniform4fv(p.ID, u.Location, 1, &v[0])
+	}
+}
+
+// SetFloat sets a float uniform
+func (p *Program) SetFloat(name string, v float32) {
+	if u, ok := p.uniform(name, gl.FLOAT); ok {
+		gl.ProgramUniform1f(p.ID, u.Location, v)
+	}
+}
+
+// SetInt sets an int or bool uniform
+func (p *Program) SetInt(name string, v int32) {
+	if u, ok := p.uniform(name, gl.INT, gl.BOOL); ok {
+		gl.ProgramUniform1i(p.ID, u.Location, v)
+	}
+}
+
+// SetSampler points a sampler uniform at a texture unit, e.g. 0 for gl.TEXTURE0
+func (p *Program) SetSampler(name string, unit int32) {
+	if u, ok := p.uniform(name, samplerTypes...); ok {
+		gl.ProgramUniform1i(p.ID, u.Location, unit)
+	}
+}
+
+var samplerTypes = []uint32{
+	gl.SAMPLER_1D, gl.SAMPLER_2D, gl.SAMPLER_3D, gl.SAMPLER_CUBE,
+	gl.SAMPLER_2D_SHADOW, gl.SAMPLER_2D_ARRAY, gl.SAMPLER_2D_MULTISAMPLE,
+	gl.INT_SAMPLER_2D, gl.UNSIGNED_INT_SAMPLER_2D,
+}
+
+// uniform looks up an active uniform and checks it has one of the expected GLSL
+// types. Unknown names and type mismatches are reported once per name rather than
+// silently writing to location -1.
+func (p *Program) uniform(name string, types ...uint32) (Uniform, bool) {
+	u, ok := p.uniforms[name]
+	if !ok {
+		p.warnOnce(name, "Unknown uniform %s in program %s / %s\n", name, p.vertexShaderName, p.fragmentShaderName)
+		return u, false
+	}
+
+	for _, t := range types {
+		if u.Type == t {
+			return u, true
+		}
+	}
+
+	p.warnOnce(name, "Uniform %s in program %s / %s is a %s, not a %s\n",
+		name, p.vertexShaderName, p.fragmentShaderName, glslTypeName(u.Type), glslTypeName(types[0]))
+	return u, false
+}
+
+func (p *Program) warnOnce(name string, format string, args ...interface{}) {
+	if p.warned[name] {
+		return
+	}
+	p.warned[name] = true
+	fmt.Printf(format, args...)
+}
+
+func glslTypeName(t uint32) string {
+	switch t {
+	case gl.FLOAT:
+		return "float"
+	case gl.FLOAT_VEC2:
+		return "vec2"
+	case gl.FLOAT_VEC3:
+		return "vec3"
+	case gl.FLOAT_VEC4:
+		return "vec4"
+	case gl.INT:
+		return "int"
+	case gl.BOOL:
+		return "bool"
+	case gl.FLOAT_MAT3:
+		return "mat3"
+	case gl.FLOAT_MAT4:
+		return "mat4"
+	case gl.SAMPLER_2D:
+		return "sampler2D"
+	case gl.SAMPLER_CUBE:
+		return "samplerCube"
+	default:
+		return fmt.Sprintf("GL type 0x%x", t)
+	}
+}
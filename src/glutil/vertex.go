@@ -0,0 +1,195 @@
+package glutil
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// VertexAttrib is a float vector attribute within an interleaved vertex. Name is
+// the input it feeds in the vertex shader.
+type VertexAttrib struct {
+	Name string
+	Size int32
+}
+
+// Attributes used by the tutorial shaders
+var (
+	Position3f = VertexAttrib{Name: "position", Size: 3}
+	Color3f    = VertexAttrib{Name: "color", Size: 3}
+	UV2f       = VertexAttrib{Name: "texture", Size: 2}
+	Normal3f   = VertexAttrib{Name: "normal", Size: 3}
+	Tangent4f  = VertexAttrib{Name: "tangent", Size: 4}
+)
+
+// VertexFormat describes the layout of an interleaved float vertex. Attributes are
+// bound to consecutive locations in the order given, matching the
+// "layout (location = N)" declarations in the shaders.
+type VertexFormat []VertexAttrib
+
+// NewVertexFormat builds a format from attributes in location order
+func NewVertexFormat(attribs ...VertexAttrib) VertexFormat {
+	return VertexFormat(attribs)
+}
+
+// Floats is the number of float32s in one vertex
+func (f VertexFormat) Floats() int {
+	n := 0
+	for _, a := range f {
+		n += int(a.Size)
+	}
+	return n
+}
+
+// Stride is the size of one vertex in bytes
+func (f VertexFormat) Stride() int32 {
+	return int32(f.Floats() * 4)
+}
+
+// Offset is the byte offset of the i'th attribute within a vertex
+func (f VertexFormat) Offset(i int) int {
+	n := 0
+	for _, a := range f[:i] {
+		n += int(a.Size)
+	}
+	return n * 4
+}
+
+// Location is the attribute location the named attribute is bound to, or -1
+func (f VertexFormat) Location(name string) int {
+	for i, a := range f {
+		if a.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Check verifies that every vertex input of a linked program is provided by the
+// format at the same location and with the same number of components
+func (f VertexFormat) Check(p *Program) error {
+	for name, attr := range p.Attributes() {
+		// Built-ins such as gl_VertexID are not fed from buffers
+		if attr.Location < 0 {
+			continue
+		}
+
+		loc := f.Location(name)
+		if loc < 0 {
+			return fmt.Errorf("vertex format has no attribute %s used by program %s / %s",
+				name, p.vertexShaderName, p.fragmentShaderName)
+		}
+		if int32(loc) != attr.Location {
+			return fmt.Errorf("attribute %s is at location %d in the vertex format but %d in program %s / %s",
+				name, loc, attr.Location, p.vertexShaderName, p.fragmentShaderName)
+		}
+		if size := attribComponents(attr.Type); size != 0 && size != f[loc].Size {
+			return fmt.Errorf("attribute %s has %d components in the vertex format but is a %s in program %s / %s",
+				name, f[loc].Size, glslTypeName(attr.Type), p.vertexShaderName, p.fragmentShaderName)
+		}
+	}
+	return nil
+}
+
+func attribComponents(xtype uint32) int32 {
+	switch xtype {
+	case gl.FLOAT:
+		return 1
+	case gl.FLOAT_VEC2:
+		return 2
+	case gl.FLOAT_VEC3:
+		return 3
+	case gl.FLOAT_VEC4:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// Mesh is interleaved vertex data uploaded into a VAO, with an optional index buffer
+type Mesh struct {
+	VAO, VBO, EBO uint32
+	Format        VertexFormat
+
+	// Count is the number of indices, or of vertices when the mesh is not indexed
+	Count int32
+}
+
+// NewMesh uploads vertices laid out as format into a new VAO/VBO. When indices
+// is non-empty an element buffer is created as well and the mesh is drawn with
+// DrawElements.
+func NewMesh(format VertexFormat, vertices []float32, indices []uint32) (*Mesh, error) {
+	floats := format.Floats()
+	if floats == 0 {
+		return nil, fmt.Errorf("empty vertex format")
+	}
+	if len(vertices) == 0 || len(vertices)%floats != 0 {
+		return nil, fmt.Errorf("vertex data has %d floats, not a multiple of the %d-float vertex format",
+			len(vertices), floats)
+	}
+	vertexCount := len(vertices) / floats
+	for _, idx := range indices {
+		if int(idx) >= vertexCount {
+			return nil, fmt.Errorf("index %d out of range for %d vertices", idx, vertexCount)
+		}
+	}
+
+	m := &Mesh{Format: format, Count: int32(vertexCount)}
+
+	// Setup the VBO/VAO
+	gl.GenVertexArrays(1, &m.VAO)
+	gl.GenBuffers(1, &m.VBO)
+
+	gl.BindVertexArray(m.VAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, m.VBO)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
+
+	if len(indices) > 0 {
+		gl.GenBuffers(1, &m.EBO)
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, m.EBO)
+		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
+		m.Count = int32(len(indices))
+	}
+
+	stride := format.Stride()
+	for i, a := range format {
+		gl.VertexAttribPointer(uint32(i), a.Size, gl.FLOAT, false, stride, gl.PtrOffset(format.Offset(i)))
+		gl.EnableVertexAttribArray(uint32(i))
+	}
+
+	gl.BindVertexArray(0)
+	return m, nil
+}
+
+// Draw issues the draw call for the whole mesh as triangles
+func (m *Mesh) Draw() {
+	gl.BindVertexArray(m.VAO)
+	if m.EBO != 0 {
+		gl.DrawElements(gl.TRIANGLES, m.Count, gl.UNSIGNED_INT, nil)
+	} else {
+		gl.DrawArrays(gl.TRIANGLES, 0, m.Count)
+	}
+	gl.BindVertexArray(0)
+}
+
+// DrawRange draws count indices starting at first, for meshes made of several
+// parts sharing one buffer. Unindexed meshes treat first and count as vertices.
+func (m *Mesh) DrawRange(first, count int32) {
+	gl.BindVertexArray(m.VAO)
+	if m.EBO != 0 {
+		gl.DrawElements(gl.TRIANGLES, count, gl.UNSIGNED_INT, gl.PtrOffset(int(first)*4))
+	} else {
+		gl.DrawArrays(gl.TRIANGLES, first, count)
+	}
+	gl.BindVertexArray(0)
+}
+
+// Delete releases the mesh's GL buffers
+func (m *Mesh) Delete() {
+	if m.EBO != 0 {
+		gl.DeleteBuffers(1, &m.EBO)
+	}
+	gl.DeleteBuffers(1, &m.VBO)
+	gl.DeleteVertexArrays(1, &m.VAO)
+	*m = Mesh{}
+}
@@ -0,0 +1,55 @@
+package glutil
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// Viewport follows the size of a window's framebuffer, which on HiDPI
+// displays is larger than the window size in screen coordinates
+type Viewport struct {
+	Width, Height int
+
+	listeners []func(width, height int)
+}
+
+// NewViewport sets the GL viewport to the window's framebuffer and keeps it
+// in step as the window is resized. The window's context must be current.
+func NewViewport(window *glfw.Window) *Viewport {
+	v := &Viewport{}
+	v.Resize(window.GetFramebufferSize())
+	window.SetFramebufferSizeCallback(func(w *glfw.Window, width, height int) {
+		v.Resize(width, height)
+	})
+	return v
+}
+
+// OnResize registers f to be called with the new framebuffer size whenever it
+// changes, e.g. to rebuild a projection or reallocate render targets. f is
+// also called straight away with the current size.
+func (v *Viewport) OnResize(f func(width, height int)) {
+	v.listeners = append(v.listeners, f)
+	f(v.Width, v.Height)
+}
+
+// Resize updates the GL viewport and notifies listeners. A minimised window
+// reports a zero sized framebuffer, which is ignored so the last size sticks.
+func (v *Viewport) Resize(width, height int) {
+	if width <= 0 || height <= 0 || (width == v.Width && height == v.Height) {
+		return
+	}
+	v.Width, v.Height = width, height
+	gl.Viewport(0, 0, int32(width), int32(height))
+
+	for _, f := range v.listeners {
+		f(width, height)
+	}
+}
+
+// Aspect is the framebuffer's width divided by its height
+func (v *Viewport) Aspect() float32 {
+	if v.Height == 0 {
+		return 1
+	}
+	return float32(v.Width) / float32(v.Height)
+}
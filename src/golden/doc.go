@@ -0,0 +1,8 @@
+// Package golden renders each tutorial scene headless and compares its last
+// frame against a checked-in PNG in testdata. It needs Mesa or a GPU and is
+// skipped when no EGL context can be made:
+//
+//	go test golden                          compare every scene
+//	go test golden -run Golden/gl4          compare only gl4
+//	go test golden -run Golden -update      rewrite the goldens from the current output
+package golden
@@ -0,0 +1,179 @@
+package golden
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"app"
+	"glutil"
+	_ "scenes/gl1"
+	_ "scenes/gl2"
+	_ "scenes/gl3"
+	_ "scenes/gl4"
+	_ "scenes/gl5"
+)
+
+var update = flag.Bool("update", false, "write the rendered frames as the new goldens")
+
+const (
+	goldenDir = "testdata"
+	diffDir   = "testdata/diff"
+
+	// threshold is the perceptual difference (0-1) above which a pixel
+	// counts as changed, and maxDiff the fraction of changed pixels a scene
+	// may have and still pass
+	threshold = 0.1
+	maxDiff   = 0.001
+)
+
+// frames is how long to run each scene. Headless contexts use a fixed clock,
+// so the last frame is always drawn at (frames-1)/60 seconds.
+var frames = map[string]int{
+	"gl1": 1,
+	"gl2": 30,
+	"gl3": 30,
+	"gl4": 30,
+	"gl5": 1,
+}
+
+func TestGolden(t *testing.T) {
+	// GL calls must stay on the thread the context is current on
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	ctx, err := glutil.NewContext(glutil.ContextConfig{Headless: true, Width: 1, Height: 1})
+	if err != nil {
+		t.Skipf("no headless GL context: %v", err)
+	}
+	ctx.Destroy()
+
+	for _, e := range app.Scenes() {
+		n, ok := frames[e.Name]
+		if !ok {
+			t.Errorf("%s has no frame count", e.Name)
+			continue
+		}
+		e := e
+		t.Run(e.Name, func(t *testing.T) {
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+			check(t, e, n)
+		})
+	}
+}
+
+// check renders a scene and compares it with its golden, or replaces the
+// golden when -update is set
+func check(t *testing.T, e app.Entry, frames int) {
+	frame := filepath.Join(t.TempDir(), e.Name+".png")
+	c := app.NewConfig(e.Name, e.Width, e.Height)
+	c.Headless = true
+	c.Frames = frames
+	c.Output = frame
+	if err := app.Run(c, e.New()); err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	actual, err := loadPNG(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join(goldenDir, e.Name+".png")
+	if *update {
+		if err := glutil.SavePNG(golden, actual); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := loadPNG(golden)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+
+	diff, changed := compare(expected, actual, threshold)
+	total := actual.Bounds().Dx() * actual.Bounds().Dy()
+	if diff != nil && float64(changed) <= maxDiff*float64(total) {
+		return
+	}
+
+	if err := os.MkdirAll(diffDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := glutil.SavePNG(filepath.Join(diffDir, e.Name+".actual.png"), actual); err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil {
+		t.Fatalf("size %v does not match golden %v", actual.Bounds().Size(), expected.Bounds().Size())
+	}
+	if err := glutil.SavePNG(filepath.Join(diffDir, e.Name+".diff.png"), diff); err != nil {
+		t.Fatal(err)
+	}
+	t.Fatalf("%d of %d pixels differ, see %s", changed, total, diffDir)
+}
+
+func loadPNG(filename string) (image.Image, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+// compare counts the pixels whose perceptual difference exceeds threshold and
+// returns an image of the golden faded to grey with those pixels in red. The
+// image is nil if the sizes differ.
+func compare(expected, actual image.Image, threshold float64) (*image.RGBA, int) {
+	bounds := expected.Bounds()
+	if bounds.Size() != actual.Bounds().Size() {
+		return nil, 0
+	}
+
+	diff := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	offset := actual.Bounds().Min.Sub(bounds.Min)
+	changed := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			e := color.RGBAModel.Convert(expected.At(x, y)).(color.RGBA)
+			a := color.RGBAModel.Convert(actual.At(x+offset.X, y+offset.Y)).(color.RGBA)
+
+			out := color.RGBA{255, 0, 0, 255}
+			if colorDelta(e, a) > threshold {
+				changed++
+			} else {
+				l := uint8(128 + luma(e)/2)
+				out = color.RGBA{l, l, l, 255}
+			}
+			diff.SetRGBA(x-bounds.Min.X, y-bounds.Min.Y, out)
+		}
+	}
+	return diff, changed
+}
+
+// colorDelta is the distance between two colours in YIQ space, weighted for
+// how sensitive the eye is to each channel and scaled to 0-1
+func colorDelta(a, b color.RGBA) float64 {
+	dr := float64(a.R) - float64(b.R)
+	dg := float64(a.G) - float64(b.G)
+	db := float64(a.B) - float64(b.B)
+
+	y := dr*0.29889531 + dg*0.58662247 + db*0.11448223
+	i := dr*0.59597799 - dg*0.27417610 - db*0.32180189
+	q := dr*0.21147017 - dg*0.52261711 + db*0.31114694
+
+	// 35215 is the largest possible value, between black and white
+	return math.Sqrt((0.5053*y*y + 0.299*i*i + 0.1957*q*q) / 35215)
+}
+
+func luma(c color.RGBA) float64 {
+	return float64(c.R)*0.299 + float64(c.G)*0.587 + float64(c.B)*0.114
+}
@@ -0,0 +1,206 @@
+package input
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+type bindingKind int
+
+const (
+	keyBinding bindingKind = iota
+	mouseBinding
+	axisBinding
+	joyButtonBinding
+)
+
+// Modifiers a key binding can require
+type modifiers struct {
+	shift, control, alt bool
+}
+
+func (m modifiers) any() bool {
+	return m.shift || m.control || m.alt
+}
+
+// binding is one input an action is bound to
+type binding struct {
+	kind   bindingKind
+	key    glfw.Key
+	mods   modifiers
+	button glfw.MouseButton
+
+	// Joystick axis or button index, and for axes which direction counts
+	index    int
+	negative bool
+}
+
+func parseBinding(name string) (binding, error) {
+	parts := strings.Split(name, "+")
+	last := parts[len(parts)-1]
+
+	// "Axis1+" splits into "Axis1" and ""
+	if last == "" && len(parts) == 2 && strings.HasPrefix(parts[0], "Axis") {
+		n, err := strconv.Atoi(strings.TrimPrefix(parts[0], "Axis"))
+		if err != nil || n < 0 {
+			return binding{}, fmt.Errorf("unknown input %q", name)
+		}
+		return binding{kind: axisBinding, index: n}, nil
+	}
+	if len(parts) == 1 && strings.HasPrefix(name, "Axis") && strings.HasSuffix(name, "-") {
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "Axis"), "-"))
+		if err != nil || n < 0 {
+			return binding{}, fmt.Errorf("unknown input %q", name)
+		}
+		return binding{kind: axisBinding, index: n, negative: true}, nil
+	}
+
+	if len(parts) == 1 {
+		if b, ok := mouseButtons[name]; ok {
+			return binding{kind: mouseBinding, button: b}, nil
+		}
+		if strings.HasPrefix(name, "Button") {
+			n, err := strconv.Atoi(strings.TrimPrefix(name, "Button"))
+			if err != nil || n < 0 {
+				return binding{}, fmt.Errorf("unknown input %q", name)
+			}
+			return binding{kind: joyButtonBinding, index: n}, nil
+		}
+	}
+
+	b := binding{kind: keyBinding}
+	for _, mod := range parts[:len(parts)-1] {
+		switch mod {
+		case "Shift":
+			b.mods.shift = true
+		case "Ctrl", "Control":
+			b.mods.control = true
+		case "Alt":
+			b.mods.alt = true
+		default:
+			return binding{}, fmt.Errorf("unknown modifier %q in %q", mod, name)
+		}
+	}
+	key, ok := keyNames[last]
+	if !ok {
+		return binding{}, fmt.Errorf("unknown input %q", name)
+	}
+	b.key = key
+	return b, nil
+}
+
+func (m *Map) keyDown(k glfw.Key) bool {
+	return m.keys[k] || m.tappedKeys[k]
+}
+
+func (m *Map) modifiers() modifiers {
+	return modifiers{
+		shift:   m.keys[glfw.KeyLeftShift] || m.keys[glfw.KeyRightShift],
+		control: m.keys[glfw.KeyLeftControl] || m.keys[glfw.KeyRightControl],
+		alt:     m.keys[glfw.KeyLeftAlt] || m.keys[glfw.KeyRightAlt],
+	}
+}
+
+func (m *Map) bindingValue(b binding) float32 {
+	switch b.kind {
+	case keyBinding:
+		if !m.keyDown(b.key) {
+			return 0
+		}
+		held := m.modifiers()
+		if (b.mods.shift && !held.shift) || (b.mods.control && !held.control) || (b.mods.alt && !held.alt) {
+			return 0
+		}
+		// A plain key gives way to a binding of the same key with modifiers
+		// that are held, so Shift+1 does not also trigger 1
+		if !b.mods.any() && m.shadowed(b.key, held) {
+			return 0
+		}
+		return 1
+
+	case mouseBinding:
+		if m.buttons[b.button] || m.tappedButtons[b.button] {
+			return 1
+		}
+
+	case axisBinding:
+		if b.index < len(m.axes) {
+			v := m.axes[b.index]
+			if b.negative {
+				v = -v
+			}
+			return m.shapeAxis(v)
+		}
+
+	case joyButtonBinding:
+		if b.index < len(m.joyBtns) && m.joyBtns[b.index] == byte(glfw.Press) {
+			return 1
+		}
+	}
+	return 0
+}
+
+// shadowed reports whether any binding of key with modifiers is satisfied by held
+func (m *Map) shadowed(key glfw.Key, held modifiers) bool {
+	for _, bs := range m.bindings {
+		for _, b := range bs {
+			if b.kind != keyBinding || b.key != key || !b.mods.any() {
+				continue
+			}
+			if (!b.mods.shift || held.shift) && (!b.mods.control || held.control) && (!b.mods.alt || held.alt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var mouseButtons = map[string]glfw.MouseButton{
+	"MouseLeft":   glfw.MouseButtonLeft,
+	"MouseRight":  glfw.MouseButtonRight,
+	"MouseMiddle": glfw.MouseButtonMiddle,
+	"Mouse4":      glfw.MouseButton4,
+	"Mouse5":      glfw.MouseButton5,
+}
+
+var keyNames = map[string]glfw.Key{
+	"A": glfw.KeyA, "B": glfw.KeyB, "C": glfw.KeyC, "D": glfw.KeyD, "E": glfw.KeyE,
+	"F": glfw.KeyF, "G": glfw.KeyG, "H": glfw.KeyH, "I": glfw.KeyI, "J": glfw.KeyJ,
+	"K": glfw.KeyK, "L": glfw.KeyL, "M": glfw.KeyM, "N": glfw.KeyN, "O": glfw.KeyO,
+	"P": glfw.KeyP, "Q": glfw.KeyQ, "R": glfw.KeyR, "S": glfw.KeyS, "T": glfw.KeyT,
+	"U": glfw.KeyU, "V": glfw.KeyV, "W": glfw.KeyW, "X": glfw.KeyX, "Y": glfw.KeyY,
+	"Z": glfw.KeyZ,
+
+	"0": glfw.Key0, "1": glfw.Key1, "2": glfw.Key2, "3": glfw.Key3, "4": glfw.Key4,
+	"5": glfw.Key5, "6": glfw.Key6, "7": glfw.Key7, "8": glfw.Key8, "9": glfw.Key9,
+
+	"F1": glfw.KeyF1, "F2": glfw.KeyF2, "F3": glfw.KeyF3, "F4": glfw.KeyF4,
+	"F5": glfw.KeyF5, "F6": glfw.KeyF6, "F7": glfw.KeyF7, "F8": glfw.KeyF8,
+	"F9": glfw.KeyF9, "F10": glfw.KeyF10, "F11": glfw.KeyF11, "F12": glfw.KeyF12,
+
+	"Space":     glfw.KeySpace,
+	"Escape":    glfw.KeyEscape,
+	"Enter":     glfw.KeyEnter,
+	"Tab":       glfw.KeyTab,
+	"Backspace": glfw.KeyBackspace,
+	"Up":        glfw.KeyUp,
+	"Down":      glfw.KeyDown,
+	"Left":      glfw.KeyLeft,
+	"Right":     glfw.KeyRight,
+	"PageUp":    glfw.KeyPageUp,
+	"PageDown":  glfw.KeyPageDown,
+	"Home":      glfw.KeyHome,
+	"End":       glfw.KeyEnd,
+	"Pause":     glfw.KeyPause,
+	"Period":    glfw.KeyPeriod,
+
+	"LeftShift":    glfw.KeyLeftShift,
+	"RightShift":   glfw.KeyRightShift,
+	"LeftControl":  glfw.KeyLeftControl,
+	"RightControl": glfw.KeyRightControl,
+	"LeftAlt":      glfw.KeyLeftAlt,
+	"RightAlt":     glfw.KeyRightAlt,
+}
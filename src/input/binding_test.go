@@ -0,0 +1,36 @@
+package input
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBindingErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", "unknown input"},
+		{"Nope", "unknown input"},
+		{"Shift+", "unknown input"},
+		{"Shift+Nope", "unknown input"},
+		{"Super+W", `unknown modifier "Super"`},
+		{"Shift+Meta+W", `unknown modifier "Meta"`},
+		{"Axisx+", "unknown input"},
+		{"Axis-1-", "unknown input"},
+		{"Axis1", "unknown input"},
+		{"Buttonx", "unknown input"},
+		{"Button-2", "unknown input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseBinding(tt.name)
+			if err == nil {
+				t.Fatalf("parseBinding(%q) succeeded", tt.name)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not mention %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+// Package input maps keys, mouse buttons and joystick axes to named actions
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// Map holds the bindings for every action and the state of the inputs they
+// are bound to. Events arrive through the window callbacks; Update turns
+// them into per-frame action values once the events have been polled.
+type Map struct {
+	bindings map[string][]binding
+	actions  map[string]*actionState
+
+	keys    map[glfw.Key]bool
+	buttons map[glfw.MouseButton]bool
+
+	// Inputs pressed since the last Update, so a tap shorter than a frame
+	// still counts
+	tappedKeys    map[glfw.Key]bool
+	tappedButtons map[glfw.MouseButton]bool
+
+	// Joystick is polled for axis and button bindings once a window is
+	// attached. It follows joysticks being plugged in and out.
+	Joystick  glfw.Joystick
+	connected bool
+	polling   bool
+	axes      []float32
+	joyBtns   []byte
+
+	// Deadzone and Curve shape axis values; see shapeAxis
+	Deadzone float32
+	Curve    float32
+
+	// Tape, when set, records the window's key and mouse button events and
+	// the joystick state each frame
+	Tape *Tape
+
+	// Replaying ignores the window and joystick, leaving the map to be fed
+	// from a tape through KeyEvent, MouseButtonEvent and SetJoystickState
+	Replaying bool
+}
+
+type actionState struct {
+	value float32
+	prev  float32
+}
+
+// NewMap makes a map with no bindings
+func NewMap() *Map {
+	return &Map{
+		bindings:      make(map[string][]binding),
+		actions:       make(map[string]*actionState),
+		keys:          make(map[glfw.Key]bool),
+		buttons:       make(map[glfw.MouseButton]bool),
+		tappedKeys:    make(map[glfw.Key]bool),
+		tappedButtons: make(map[glfw.MouseButton]bool),
+		Joystick:      glfw.Joystick1,
+		Deadzone:      DefaultDeadzone,
+		Curve:         DefaultCurve,
+	}
+}
+
+// Bind replaces an action's bindings. Names are keys such as "W", "F12",
+// "Space" or "Shift+1", mouse buttons such as "MouseLeft", and joystick
+// inputs such as "Button0", "Axis1+" or "Axis1-" for each direction of an axis.
+func (m *Map) Bind(action string, names ...string) error {
+	bs := make([]binding, 0, len(names))
+	for _, name := range names {
+		b, err := parseBinding(name)
+		if err != nil {
+			return fmt.Errorf("action %s: %v", action, err)
+		}
+		bs = append(bs, b)
+	}
+	m.bindings[action] = bs
+	if m.actions[action] == nil {
+		m.actions[action] = &actionState{}
+	}
+	return nil
+}
+
+// BindAll binds every action in a set of bindings
+func (m *Map) BindAll(bindings map[string][]string) error {
+	for action, names := range bindings {
+		if err := m.Bind(action, names...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load reads bindings from a JSON file of action names to lists of input
+// names, e.g. {"move_forward": ["W", "Up", "Axis1-"]}. Actions in the file
+// replace any existing bindings for them; others are left alone.
+func (m *Map) Load(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var bindings map[string][]string
+	if err := json.Unmarshal(data, &bindings); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	if err := m.BindAll(bindings); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	return nil
+}
+
+// Actions lists the bound actions in name order
+func (m *Map) Actions() []string {
+	names := make([]string, 0, len(m.bindings))
+	for name := range m.bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Attach feeds a window's key and mouse button events into the map and turns
+// on joystick polling. It replaces the window's key and mouse button callbacks
+// and GLFW's joystick callback.
+func (m *Map) Attach(w *glfw.Window) {
+	w.SetKeyCallback(func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		if m.Replaying {
+			return
+		}
+		if m.Tape != nil {
+			m.Tape.Add(Event{Type: KeyInput, Key: key, Action: action})
+		}
+		m.KeyEvent(key, action)
+	})
+	w.SetMouseButtonCallback(func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		if m.Replaying {
+			return
+		}
+		if m.Tape != nil {
+			m.Tape.Add(Event{Type: ButtonInput, Button: button, Action: action})
+		}
+		m.MouseButtonEvent(button, action)
+	})
+	m.polling = true
+	m.watchJoysticks()
+}
+
+// KeyEvent records a key going up or down
+func (m *Map) KeyEvent(key glfw.Key, action glfw.Action) {
+	switch action {
+	case glfw.Press:
+		m.keys[key] = true
+		m.tappedKeys[key] = true
+	case glfw.Release:
+		m.keys[key] = false
+	}
+}
+
+// MouseButtonEvent records a mouse button going up or down
+func (m *Map) MouseButtonEvent(button glfw.MouseButton, action glfw.Action) {
+	switch action {
+	case glfw.Press:
+		m.buttons[button] = true
+		m.tappedButtons[button] = true
+	case glfw.Release:
+		m.buttons[button] = false
+	}
+}
+
+// Update works out every action's value for this frame. Call it once per
+// frame after polling events.
+func (m *Map) Update() {
+	if !m.Replaying {
+		m.pollJoystick()
+		if m.Tape != nil {
+			m.Tape.joystick(m.axes, m.joyBtns)
+		}
+	}
+
+	for action, state := range m.actions {
+		state.prev = state.value
+		state.value = 0
+		for _, b := range m.bindings[action] {
+			if v := m.bindingValue(b); v > state.value {
+				state.value = v
+			}
+		}
+	}
+
+	for k := range m.tappedKeys {
+		delete(m.tappedKeys, k)
+	}
+	for b := range m.tappedButtons {
+		delete(m.tappedButtons, b)
+	}
+}
+
+// Value is how far an action is applied, from 0 to 1. Keys and buttons are
+// 0 or 1; axes give the shaped distance along the bound direction.
+func (m *Map) Value(action string) float32 {
+	if s, ok := m.actions[action]; ok {
+		return s.value
+	}
+	return 0
+}
+
+// Held reports whether an action is applied more than halfway
+func (m *Map) Held(action string) bool {
+	return m.Value(action) > 0.5
+}
+
+// Pressed reports whether an action started being held this frame
+func (m *Map) Pressed(action string) bool {
+	s, ok := m.actions[action]
+	return ok && s.value > 0.5 && s.prev <= 0.5
+}
+
+// Released reports whether an action stopped being held this frame
+func (m *Map) Released(action string) bool {
+	s, ok := m.actions[action]
+	return ok && s.value <= 0.5 && s.prev > 0.5
+}
+
+// Axis combines two opposing actions into one value from -1 to 1, e.g. a
+// stick's left and right directions
+func (m *Map) Axis(negative, positive string) float32 {
+	return m.Value(positive) - m.Value(negative)
+}
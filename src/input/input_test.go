@@ -0,0 +1,134 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+func press(k glfw.Key) func(*Map)   { return func(m *Map) { m.KeyEvent(k, glfw.Press) } }
+func release(k glfw.Key) func(*Map) { return func(m *Map) { m.KeyEvent(k, glfw.Release) } }
+
+func click(b glfw.MouseButton, action glfw.Action) func(*Map) {
+	return func(m *Map) { m.MouseButtonEvent(b, action) }
+}
+
+// frame is the events of one frame and the actions expected to be pressed,
+// held and released after its Update. Any action not listed must be false.
+type frame struct {
+	events                  []func(*Map)
+	pressed, held, released []string
+}
+
+func TestMapUpdate(t *testing.T) {
+	bindings := map[string][]string{
+		"jump":     {"Space", "MouseLeft"},
+		"bookmark": {"N"},
+		"save":     {"Shift+N"},
+	}
+
+	tests := []struct {
+		name   string
+		frames []frame
+	}{
+		{
+			name: "press, hold, release",
+			frames: []frame{
+				{events: []func(*Map){press(glfw.KeySpace)}, pressed: []string{"jump"}, held: []string{"jump"}},
+				{held: []string{"jump"}},
+				{events: []func(*Map){release(glfw.KeySpace)}, released: []string{"jump"}},
+				{},
+			},
+		},
+		{
+			name: "tap within a frame",
+			frames: []frame{
+				{events: []func(*Map){press(glfw.KeySpace), release(glfw.KeySpace)}, pressed: []string{"jump"}, held: []string{"jump"}},
+				{released: []string{"jump"}},
+			},
+		},
+		{
+			name: "second binding of a held action",
+			frames: []frame{
+				{events: []func(*Map){press(glfw.KeySpace)}, pressed: []string{"jump"}, held: []string{"jump"}},
+				{events: []func(*Map){click(glfw.MouseButtonLeft, glfw.Press)}, held: []string{"jump"}},
+				{events: []func(*Map){release(glfw.KeySpace)}, held: []string{"jump"}},
+				{events: []func(*Map){click(glfw.MouseButtonLeft, glfw.Release)}, released: []string{"jump"}},
+			},
+		},
+		{
+			name: "bare key",
+			frames: []frame{
+				{events: []func(*Map){press(glfw.KeyN)}, pressed: []string{"bookmark"}, held: []string{"bookmark"}},
+			},
+		},
+		{
+			name: "shift shadows the bare key",
+			frames: []frame{
+				{events: []func(*Map){press(glfw.KeyLeftShift)}},
+				{events: []func(*Map){press(glfw.KeyN)}, pressed: []string{"save"}, held: []string{"save"}},
+				{events: []func(*Map){release(glfw.KeyN)}, released: []string{"save"}},
+			},
+		},
+		{
+			name: "right shift counts too",
+			frames: []frame{
+				{events: []func(*Map){press(glfw.KeyRightShift), press(glfw.KeyN)}, pressed: []string{"save"}, held: []string{"save"}},
+			},
+		},
+		{
+			name: "letting go of shift falls back to the bare key",
+			frames: []frame{
+				{events: []func(*Map){press(glfw.KeyLeftShift), press(glfw.KeyN)}, pressed: []string{"save"}, held: []string{"save"}},
+				{events: []func(*Map){release(glfw.KeyLeftShift)}, pressed: []string{"bookmark"}, held: []string{"bookmark"}, released: []string{"save"}},
+			},
+		},
+		{
+			name: "unbound modifier does not shadow",
+			frames: []frame{
+				{events: []func(*Map){press(glfw.KeyLeftControl), press(glfw.KeyN)}, pressed: []string{"bookmark"}, held: []string{"bookmark"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMap()
+			if err := m.BindAll(bindings); err != nil {
+				t.Fatal(err)
+			}
+			for i, f := range tt.frames {
+				for _, e := range f.events {
+					e(m)
+				}
+				m.Update()
+
+				for _, check := range []struct {
+					what string
+					want []string
+					got  func(string) bool
+				}{
+					{"pressed", f.pressed, m.Pressed},
+					{"held", f.held, m.Held},
+					{"released", f.released, m.Released},
+				} {
+					for action := range bindings {
+						want := contains(check.want, action)
+						if check.got(action) != want {
+							t.Errorf("frame %d: %s %s = %v, want %v", i, action, check.what, !want, want)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package input
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// Analog axis shaping defaults
+const (
+	DefaultDeadzone = 0.15
+	DefaultCurve    = 2.0
+)
+
+// watchJoysticks picks the first joystick already plugged in and follows
+// joysticks being connected and disconnected afterwards
+func (m *Map) watchJoysticks() {
+	m.connected = false
+	for j := glfw.Joystick1; j <= glfw.JoystickLast; j++ {
+		if glfw.JoystickPresent(j) {
+			m.useJoystick(j)
+			break
+		}
+	}
+
+	glfw.SetJoystickCallback(func(joy, event int) {
+		j := glfw.Joystick(joy)
+		switch glfw.MonitorEvent(event) {
+		case glfw.Connected:
+			if !m.connected {
+				m.useJoystick(j)
+			}
+		case glfw.Disconnected:
+			if m.connected && j == m.Joystick {
+				fmt.Printf("Joystick %d disconnected\n", joy)
+				m.connected = false
+				m.axes, m.joyBtns = nil, nil
+				// Fall back to any other joystick still plugged in
+				for other := glfw.Joystick1; other <= glfw.JoystickLast; other++ {
+					if other != j && glfw.JoystickPresent(other) {
+						m.useJoystick(other)
+						break
+					}
+				}
+			}
+		}
+	})
+}
+
+func (m *Map) useJoystick(j glfw.Joystick) {
+	m.Joystick = j
+	m.connected = true
+	fmt.Printf("Using joystick %d: %s\n", j, glfw.GetJoystickName(j))
+}
+
+func (m *Map) pollJoystick() {
+	m.axes, m.joyBtns = nil, nil
+	if m.polling && m.connected {
+		m.axes = glfw.GetJoystickAxes(m.Joystick)
+		m.joyBtns = glfw.GetJoystickButtons(m.Joystick)
+	}
+}
+
+// shapeAxis turns how far an axis is pushed in a binding's direction into an
+// action value. Anything inside the deadzone reads 0, so a stick resting
+// slightly off centre does not drift, and the rest of the travel is rescaled
+// to 0-1 and raised to Curve for finer control near the centre.
+func (m *Map) shapeAxis(v float32) float32 {
+	if v <= m.Deadzone {
+		return 0
+	}
+	if v >= 1 {
+		return 1
+	}
+	v = (v - m.Deadzone) / (1 - m.Deadzone)
+	return float32(math.Pow(float64(v), float64(m.Curve)))
+}
+
+// SetJoystickState stands in for polling the joystick while replaying
+func (m *Map) SetJoystickState(axes []float32, buttons []byte) {
+	m.axes, m.joyBtns = axes, buttons
+}
@@ -0,0 +1,80 @@
+package input
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func approx(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
+
+func TestShapeAxis(t *testing.T) {
+	tests := []struct {
+		deadzone, curve float32
+		v               float32
+		want            float32
+	}{
+		// Inside the deadzone, and the wrong direction
+		{0.2, 2, 0, 0},
+		{0.2, 2, 0.1, 0},
+		{0.2, 2, 0.2, 0},
+		{0.2, 2, -0.5, 0},
+
+		// Just past the deadzone starts from 0 rather than jumping
+		{0.2, 1, 0.2001, 0.000125},
+		{0.2, 2, 0.2001, 0},
+
+		// Full deflection, and beyond it, is 1 whatever the curve
+		{0.2, 2, 1, 1},
+		{0.2, 3, 1, 1},
+		{0.2, 2, 1.5, 1},
+
+		// Curves above 1 give finer control near the centre
+		{0.2, 1, 0.6, 0.5},
+		{0.2, 2, 0.6, 0.25},
+		{0.2, 3, 0.6, 0.125},
+		{0, 2, 0.5, 0.25},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("deadzone %v curve %v at %v", tt.deadzone, tt.curve, tt.v), func(t *testing.T) {
+			m := NewMap()
+			m.Deadzone, m.Curve = tt.deadzone, tt.curve
+			if got := m.shapeAxis(tt.v); !approx(got, tt.want) {
+				t.Errorf("shapeAxis(%v) = %v, want %v", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapAxis(t *testing.T) {
+	tests := []struct {
+		axes []float32
+		want float32
+	}{
+		{[]float32{0}, 0},
+		{[]float32{1}, 1},
+		{[]float32{-1}, -1},
+		{[]float32{0.1}, 0},
+		{[]float32{-0.1}, 0},
+		{[]float32{0.6}, 0.25},
+		{[]float32{-0.6}, -0.25},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.axes), func(t *testing.T) {
+			m := NewMap()
+			m.Deadzone, m.Curve = 0.2, 2
+			if err := m.BindAll(map[string][]string{"left": {"Axis0-"}, "right": {"Axis0+"}}); err != nil {
+				t.Fatal(err)
+			}
+			m.SetJoystickState(tt.axes, nil)
+			m.Replaying = true
+			m.Update()
+			if got := m.Axis("left", "right"); !approx(got, tt.want) {
+				t.Errorf("Axis = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
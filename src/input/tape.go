@@ -0,0 +1,101 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// EventType is the kind of input an Event records
+type EventType int
+
+// EventType consts
+const (
+	KeyInput EventType = iota
+	ButtonInput
+	CursorInput
+	ScrollInput
+)
+
+// Event is one window input event. Key and mouse button events use Action;
+// cursor positions and scroll offsets use X and Y.
+type Event struct {
+	Type   EventType        `json:"type"`
+	Key    glfw.Key         `json:"key,omitempty"`
+	Button glfw.MouseButton `json:"button,omitempty"`
+	Action glfw.Action      `json:"action,omitempty"`
+	X      float64          `json:"x,omitempty"`
+	Y      float64          `json:"y,omitempty"`
+}
+
+// TapeFrame is the input of one frame: the events in the order they arrived,
+// the joystick state and the frame's delta time in seconds
+type TapeFrame struct {
+	Delta   float64   `json:"delta"`
+	Events  []Event   `json:"events,omitempty"`
+	Axes    []float32 `json:"axes,omitempty"`
+	Buttons []byte    `json:"buttons,omitempty"`
+}
+
+// Tape is a frame by frame recording of input. Replaying it feeds a program
+// the same events and delta times so it follows the same course as when it
+// was recorded.
+type Tape struct {
+	Frames []TapeFrame `json:"frames"`
+
+	pending TapeFrame
+	next    int
+}
+
+// LoadTape reads a tape saved by Save
+func LoadTape(filename string) (*Tape, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tape{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return t, nil
+}
+
+// Save writes the recorded frames to a JSON file
+func (t *Tape) Save(filename string) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// Add records an event in the current frame
+func (t *Tape) Add(e Event) {
+	t.pending.Events = append(t.pending.Events, e)
+}
+
+// joystick records the joystick state of the current frame
+func (t *Tape) joystick(axes []float32, buttons []byte) {
+	t.pending.Axes = append([]float32(nil), axes...)
+	t.pending.Buttons = append([]byte(nil), buttons...)
+}
+
+// EndFrame finishes the current frame with the delta time it was run with
+func (t *Tape) EndFrame(delta float64) {
+	t.pending.Delta = delta
+	t.Frames = append(t.Frames, t.pending)
+	t.pending = TapeFrame{}
+}
+
+// Next returns the next frame to replay, or false once they have all been
+// replayed
+func (t *Tape) Next() (TapeFrame, bool) {
+	if t.next >= len(t.Frames) {
+		return TapeFrame{}, false
+	}
+	f := t.Frames[t.next]
+	t.next++
+	return f, true
+}
@@ -0,0 +1,65 @@
+package input
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+func TestTapeRoundTrip(t *testing.T) {
+	frames := []TapeFrame{
+		{
+			Delta: 1.0 / 60,
+			Events: []Event{
+				{Type: KeyInput, Key: glfw.KeyW, Action: glfw.Press},
+				{Type: CursorInput, X: 320.5, Y: 240.25},
+			},
+		},
+		{Delta: 1.0 / 30},
+		{
+			Delta: 0.0125,
+			Events: []Event{
+				{Type: ButtonInput, Button: glfw.MouseButtonLeft, Action: glfw.Release},
+				{Type: ScrollInput, Y: -1},
+				{Type: KeyInput, Key: glfw.KeyW, Action: glfw.Release},
+			},
+			Axes:    []float32{0.5, -0.25},
+			Buttons: []byte{1, 0},
+		},
+	}
+
+	rec := &Tape{}
+	for _, f := range frames {
+		for _, e := range f.Events {
+			rec.Add(e)
+		}
+		if f.Axes != nil {
+			rec.joystick(f.Axes, f.Buttons)
+		}
+		rec.EndFrame(f.Delta)
+	}
+
+	filename := filepath.Join(t.TempDir(), "tape.json")
+	if err := rec.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	tape, err := LoadTape(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range frames {
+		got, ok := tape.Next()
+		if !ok {
+			t.Fatalf("tape ended after %d frames, want %d", i, len(frames))
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("frame %d = %+v, want %+v", i, got, want)
+		}
+	}
+	if f, ok := tape.Next(); ok {
+		t.Errorf("extra frame %+v", f)
+	}
+}
@@ -0,0 +1,852 @@
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"glutil"
+)
+
+// The parts of the glTF 2.0 JSON schema the importer understands
+
+type gltfDoc struct {
+	Asset struct {
+		Version string `json:"version"`
+	} `json:"asset"`
+	Scene       *int             `json:"scene"`
+	Scenes      []gltfScene      `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes"`
+	Meshes      []gltfMesh       `json:"meshes"`
+	Accessors   []gltfAccessor   `json:"accessors"`
+	BufferViews []gltfBufferView `json:"bufferViews"`
+	Buffers     []gltfBuffer     `json:"buffers"`
+	Materials   []gltfMaterial   `json:"materials"`
+	Textures    []gltfTexture    `json:"textures"`
+	Images      []gltfImage      `json:"images"`
+}
+
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type gltfNode struct {
+	Name        string    `json:"name"`
+	Children    []int     `json:"children"`
+	Mesh        *int      `json:"mesh"`
+	Matrix      []float32 `json:"matrix"`
+	Translation []float32 `json:"translation"`
+	Rotation    []float32 `json:"rotation"`
+	Scale       []float32 `json:"scale"`
+}
+
+type gltfMesh struct {
+	Name       string          `json:"name"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices"`
+	Material   *int           `json:"material"`
+	Mode       *int           `json:"mode"`
+}
+
+type gltfAccessor struct {
+	BufferView    *int            `json:"bufferView"`
+	ByteOffset    int             `json:"byteOffset"`
+	ComponentType int             `json:"componentType"`
+	Normalized    bool            `json:"normalized"`
+	Count         int             `json:"count"`
+	Type          string          `json:"type"`
+	Sparse        json.RawMessage `json:"sparse"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride"`
+}
+
+type gltfBuffer struct {
+	URI        string `json:"uri"`
+	ByteLength int    `json:"byteLength"`
+}
+
+type gltfTextureRef struct {
+	Index    int `json:"index"`
+	TexCoord int `json:"texCoord"`
+}
+
+type gltfMaterial struct {
+	Name                 string `json:"name"`
+	PBRMetallicRoughness struct {
+		BaseColorFactor          []float32       `json:"baseColorFactor"`
+		BaseColorTexture         *gltfTextureRef `json:"baseColorTexture"`
+		MetallicFactor           *float32        `json:"metallicFactor"`
+		RoughnessFactor          *float32        `json:"roughnessFactor"`
+		MetallicRoughnessTexture *gltfTextureRef `json:"metallicRoughnessTexture"`
+	} `json:"pbrMetallicRoughness"`
+	AlphaMode   string `json:"alphaMode"`
+	DoubleSided bool   `json:"doubleSided"`
+}
+
+type gltfTexture struct {
+	Source *int `json:"source"`
+}
+
+type gltfImage struct {
+	URI        string `json:"uri"`
+	MimeType   string `json:"mimeType"`
+	BufferView *int   `json:"bufferView"`
+}
+
+// glTF constants
+const (
+	gltfModeTriangles = 4
+
+	gltfByte          = 5120
+	gltfUnsignedByte  = 5121
+	gltfShort         = 5122
+	gltfUnsignedShort = 5123
+	gltfUnsignedInt   = 5125
+	gltfFloat         = 5126
+
+	glbMagic     = 0x46546c67 // "glTF"
+	glbChunkJSON = 0x4e4f534a // "JSON"
+	glbChunkBIN  = 0x004e4942 // "BIN\0"
+
+	// maxZeroAccessorBytes caps the zeros made for an accessor without a
+	// buffer view
+	maxZeroAccessorBytes = 1 << 28
+)
+
+// PBRMaterial holds the metallic-roughness parameters of a glTF material.
+// Texture fields index into GLTF.Images, -1 when absent.
+type PBRMaterial struct {
+	Name                     string
+	BaseColor                mgl32.Vec4
+	BaseColorTexture         int
+	Metallic                 float32
+	Roughness                float32
+	MetallicRoughnessTexture int
+	AlphaMode                string
+	DoubleSided              bool
+}
+
+// Primitive is one glTF primitive within a GLTFMesh's shared buffers.
+// Material indexes GLTF.Materials, -1 for the default material.
+type Primitive struct {
+	First    int
+	Count    int
+	Material int
+}
+
+// GLTFMesh is a glTF mesh with all its primitives packed into one vertex and
+// index buffer
+type GLTFMesh struct {
+	Name       string
+	Vertices   []float32
+	Indices    []uint32
+	Primitives []Primitive
+}
+
+// Node is a node of the default scene that has a mesh, with its transform
+// already multiplied through its parents
+type Node struct {
+	Name  string
+	Mesh  int
+	World mgl32.Mat4
+}
+
+// GLTF is a glTF 2.0 asset decoded into CPU-side vertex data and images
+type GLTF struct {
+	Format    glutil.VertexFormat
+	Meshes    []GLTFMesh
+	Nodes     []Node
+	Materials []PBRMaterial
+	Images    []*image.RGBA
+}
+
+type gltfLoader struct {
+	filename string
+	doc      gltfDoc
+	bin      []byte
+	buffers  [][]byte
+}
+
+// LoadGLTF reads a .gltf (JSON) or .glb (binary) file. Buffers and images may be
+// embedded as data URIs, stored in the GLB binary chunk, or be external files
+// next to the asset. Vertices are emitted in the given format, which may
+// contain Position3f, UV2f and Normal3f; only triangle primitives are supported.
+func LoadGLTF(filename string, format glutil.VertexFormat) (*GLTF, error) {
+	for _, a := range format {
+		if a != glutil.Position3f && a != glutil.UV2f && a != glutil.Normal3f {
+			return nil, fmt.Errorf("glTF import cannot provide vertex attribute %s", a.Name)
+		}
+	}
+
+	data, err := glutil.ReadAsset(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &gltfLoader{filename: filename}
+	if len(data) >= 4 && binary.LittleEndian.Uint32(data) == glbMagic {
+		data, l.bin, err = splitGLB(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+	}
+
+	if err := json.Unmarshal(data, &l.doc); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	if !strings.HasPrefix(l.doc.Asset.Version, "2.") {
+		return nil, fmt.Errorf("%s: unsupported glTF version %q", filename, l.doc.Asset.Version)
+	}
+
+	out, err := l.load(format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	fmt.Printf("Loaded %s: %d meshes, %d nodes, %d materials, %d images\n",
+		filename, len(out.Meshes), len(out.Nodes), len(out.Materials), len(out.Images))
+	return out, nil
+}
+
+// splitGLB returns the JSON and BIN chunks of a binary glTF container
+func splitGLB(data []byte) ([]byte, []byte, error) {
+	if len(data) < 20 {
+		return nil, nil, fmt.Errorf("truncated GLB header")
+	}
+	if version := binary.LittleEndian.Uint32(data[4:]); version != 2 {
+		return nil, nil, fmt.Errorf("unsupported GLB version %d", version)
+	}
+	length := int(binary.LittleEndian.Uint32(data[8:]))
+	if length > len(data) {
+		return nil, nil, fmt.Errorf("truncated GLB: header says %d bytes, have %d", length, len(data))
+	}
+
+	var jsonChunk, binChunk []byte
+	for offset := 12; offset+8 <= length; {
+		chunkLen := int(binary.LittleEndian.Uint32(data[offset:]))
+		chunkType := binary.LittleEndian.Uint32(data[offset+4:])
+		start, end := offset+8, offset+8+chunkLen
+		if end > length {
+			return nil, nil, fmt.Errorf("truncated GLB chunk")
+		}
+
+		switch chunkType {
+		case glbChunkJSON:
+			jsonChunk = data[start:end]
+		case glbChunkBIN:
+			if binChunk == nil {
+				binChunk = data[start:end]
+			}
+		}
+		// Chunks are padded to 4 bytes
+		offset = end + (4-chunkLen%4)%4
+	}
+
+	if jsonChunk == nil {
+		return nil, nil, fmt.Errorf("GLB has no JSON chunk")
+	}
+	return jsonChunk, binChunk, nil
+}
+
+func (l *gltfLoader) load(format glutil.VertexFormat) (*GLTF, error) {
+	out := &GLTF{Format: format}
+
+	l.buffers = make([][]byte, len(l.doc.Buffers))
+	for i, b := range l.doc.Buffers {
+		data, err := l.buffer(i, b)
+		if err != nil {
+			return nil, err
+		}
+		l.buffers[i] = data
+	}
+
+	for i, img := range l.doc.Images {
+		rgba, err := l.image(i, img)
+		if err != nil {
+			return nil, err
+		}
+		out.Images = append(out.Images, rgba)
+	}
+
+	for _, m := range l.doc.Materials {
+		out.Materials = append(out.Materials, l.material(m))
+	}
+
+	for i, m := range l.doc.Meshes {
+		mesh, err := l.mesh(m, format)
+		if err != nil {
+			return nil, fmt.Errorf("mesh %d: %v", i, err)
+		}
+		out.Meshes = append(out.Meshes, mesh)
+	}
+
+	// Walk the default scene, or every root node if the file has no scenes
+	var roots []int
+	if len(l.doc.Scenes) > 0 {
+		scene := 0
+		if l.doc.Scene != nil {
+			scene = *l.doc.Scene
+		}
+		if scene < 0 || scene >= len(l.doc.Scenes) {
+			return nil, fmt.Errorf("scene %d out of range", scene)
+		}
+		roots = l.doc.Scenes[scene].Nodes
+	} else {
+		roots = l.rootNodes()
+	}
+
+	visited := make(map[int]bool)
+	for _, root := range roots {
+		if err := l.walk(root, mgl32.Ident4(), visited, out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (l *gltfLoader) rootNodes() []int {
+	isChild := make(map[int]bool)
+	for _, n := range l.doc.Nodes {
+		for _, c := range n.Children {
+			isChild[c] = true
+		}
+	}
+	var roots []int
+	for i := range l.doc.Nodes {
+		if !isChild[i] {
+			roots = append(roots, i)
+		}
+	}
+	return roots
+}
+
+func (l *gltfLoader) walk(index int, parent mgl32.Mat4, visited map[int]bool, out *GLTF) error {
+	if index < 0 || index >= len(l.doc.Nodes) {
+		return fmt.Errorf("node %d out of range", index)
+	}
+	if visited[index] {
+		return fmt.Errorf("node %d appears twice in the hierarchy", index)
+	}
+	visited[index] = true
+
+	n := l.doc.Nodes[index]
+	world := parent.Mul4(nodeTransform(n))
+
+	if n.Mesh != nil {
+		if *n.Mesh < 0 || *n.Mesh >= len(l.doc.Meshes) {
+			return fmt.Errorf("node %d: mesh %d out of range", index, *n.Mesh)
+		}
+		out.Nodes = append(out.Nodes, Node{Name: n.Name, Mesh: *n.Mesh, World: world})
+	}
+
+	for _, child := range n.Children {
+		if err := l.walk(child, world, visited, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nodeTransform is the node's local matrix, either given directly or as T * R * S
+func nodeTransform(n gltfNode) mgl32.Mat4 {
+	if len(n.Matrix) == 16 {
+		var m mgl32.Mat4
+		copy(m[:], n.Matrix)
+		return m
+	}
+
+	t := mgl32.Ident4()
+	if len(n.Translation) == 3 {
+		t = mgl32.Translate3D(n.Translation[0], n.Translation[1], n.Translation[2])
+	}
+	r := mgl32.Ident4()
+	if len(n.Rotation) == 4 {
+		// glTF stores quaternions as x, y, z, w
+		q := mgl32.Quat{W: n.Rotation[3], V: mgl32.Vec3{n.Rotation[0], n.Rotation[1], n.Rotation[2]}}
+		r = q.Normalize().Mat4()
+	}
+	s := mgl32.Ident4()
+	if len(n.Scale) == 3 {
+		s = mgl32.Scale3D(n.Scale[0], n.Scale[1], n.Scale[2])
+	}
+	return t.Mul4(r).Mul4(s)
+}
+
+// buffer resolves a buffer from a data URI, an external file, or the GLB BIN chunk
+func (l *gltfLoader) buffer(index int, b gltfBuffer) ([]byte, error) {
+	var data []byte
+	var err error
+	switch {
+	case b.URI == "":
+		if index != 0 || l.bin == nil {
+			return nil, fmt.Errorf("buffer %d has no uri and there is no GLB binary chunk", index)
+		}
+		data = l.bin
+	default:
+		data, err = l.readURI(b.URI)
+		if err != nil {
+			return nil, fmt.Errorf("buffer %d: %v", index, err)
+		}
+	}
+
+	if b.ByteLength < 0 {
+		return nil, fmt.Errorf("buffer %d has negative byteLength %d", index, b.ByteLength)
+	}
+	if len(data) < b.ByteLength {
+		return nil, fmt.Errorf("buffer %d is %d bytes, expected %d", index, len(data), b.ByteLength)
+	}
+	return data[:b.ByteLength], nil
+}
+
+func (l *gltfLoader) readURI(uri string) ([]byte, error) {
+	if strings.HasPrefix(uri, "data:") {
+		comma := strings.IndexByte(uri, ',')
+		if comma < 0 || !strings.HasSuffix(uri[:comma], ";base64") {
+			return nil, fmt.Errorf("unsupported data uri")
+		}
+		return base64.StdEncoding.DecodeString(uri[comma+1:])
+	}
+	return glutil.ReadAsset(filepath.Join(filepath.Dir(l.filename), filepath.FromSlash(uri)))
+}
+
+func (l *gltfLoader) image(index int, img gltfImage) (*image.RGBA, error) {
+	name := fmt.Sprintf("%s image %d", l.filename, index)
+
+	switch {
+	case img.BufferView != nil:
+		data, err := l.bufferView(*img.BufferView)
+		if err != nil {
+			return nil, fmt.Errorf("image %d: %v", index, err)
+		}
+		return glutil.DecodeImage(bytes.NewReader(data), name)
+	case strings.HasPrefix(img.URI, "data:"):
+		data, err := l.readURI(img.URI)
+		if err != nil {
+			return nil, fmt.Errorf("image %d: %v", index, err)
+		}
+		return glutil.DecodeImage(bytes.NewReader(data), name)
+	default:
+		return glutil.LoadImage(filepath.Join(filepath.Dir(l.filename), filepath.FromSlash(img.URI)))
+	}
+}
+
+func (l *gltfLoader) bufferView(index int) ([]byte, error) {
+	if index < 0 || index >= len(l.doc.BufferViews) {
+		return nil, fmt.Errorf("buffer view %d out of range", index)
+	}
+	v := l.doc.BufferViews[index]
+	if v.Buffer < 0 || v.Buffer >= len(l.buffers) {
+		return nil, fmt.Errorf("buffer view %d: buffer %d out of range", index, v.Buffer)
+	}
+	buf := l.buffers[v.Buffer]
+	if v.ByteOffset < 0 || v.ByteLength < 0 || v.ByteStride < 0 {
+		return nil, fmt.Errorf("buffer view %d has a negative offset, length or stride", index)
+	}
+	if v.ByteOffset > len(buf) || v.ByteLength > len(buf)-v.ByteOffset {
+		return nil, fmt.Errorf("buffer view %d overruns its buffer", index)
+	}
+	return buf[v.ByteOffset : v.ByteOffset+v.ByteLength], nil
+}
+
+func (l *gltfLoader) material(m gltfMaterial) PBRMaterial {
+	pbr := m.PBRMetallicRoughness
+	mat := PBRMaterial{
+		Name:                     m.Name,
+		BaseColor:                mgl32.Vec4{1, 1, 1, 1},
+		BaseColorTexture:         -1,
+		Metallic:                 1,
+		Roughness:                1,
+		MetallicRoughnessTexture: -1,
+		AlphaMode:                m.AlphaMode,
+		DoubleSided:              m.DoubleSided,
+	}
+	if len(pbr.BaseColorFactor) == 4 {
+		copy(mat.BaseColor[:], pbr.BaseColorFactor)
+	}
+	if pbr.MetallicFactor != nil {
+		mat.Metallic = *pbr.MetallicFactor
+	}
+	if pbr.RoughnessFactor != nil {
+		mat.Roughness = *pbr.RoughnessFactor
+	}
+	if pbr.BaseColorTexture != nil {
+		mat.BaseColorTexture = l.textureImage(pbr.BaseColorTexture.Index)
+	}
+	if pbr.MetallicRoughnessTexture != nil {
+		mat.MetallicRoughnessTexture = l.textureImage(pbr.MetallicRoughnessTexture.Index)
+	}
+	if mat.AlphaMode == "" {
+		mat.AlphaMode = "OPAQUE"
+	}
+	return mat
+}
+
+// textureImage maps a texture index to the image it samples, or -1
+func (l *gltfLoader) textureImage(index int) int {
+	if index < 0 || index >= len(l.doc.Textures) {
+		return -1
+	}
+	src := l.doc.Textures[index].Source
+	if src == nil || *src < 0 || *src >= len(l.doc.Images) {
+		return -1
+	}
+	return *src
+}
+
+func (l *gltfLoader) mesh(m gltfMesh, format glutil.VertexFormat) (GLTFMesh, error) {
+	out := GLTFMesh{Name: m.Name}
+
+	for i, prim := range m.Primitives {
+		if prim.Mode != nil && *prim.Mode != gltfModeTriangles {
+			return out, fmt.Errorf("primitive %d: unsupported mode %d", i, *prim.Mode)
+		}
+
+		posIndex, ok := prim.Attributes["POSITION"]
+		if !ok {
+			return out, fmt.Errorf("primitive %d has no POSITION", i)
+		}
+		positions, err := l.accessor(posIndex, "VEC3")
+		if err != nil {
+			return out, fmt.Errorf("primitive %d POSITION: %v", i, err)
+		}
+		count := len(positions) / 3
+
+		attribs := map[string][]float32{glutil.Position3f.Name: positions}
+		for _, a := range []struct {
+			gltfName, accessorType string
+			attrib                 glutil.VertexAttrib
+		}{
+			{"TEXCOORD_0", "VEC2", glutil.UV2f},
+			{"NORMAL", "VEC3", glutil.Normal3f},
+		} {
+			if format.Location(a.attrib.Name) < 0 {
+				continue
+			}
+			index, ok := prim.Attributes[a.gltfName]
+			if !ok {
+				// Missing attributes are zero filled
+				attribs[a.attrib.Name] = make([]float32, count*int(a.attrib.Size))
+				continue
+			}
+			values, err := l.accessor(index, a.accessorType)
+			if err != nil {
+				return out, fmt.Errorf("primitive %d %s: %v", i, a.gltfName, err)
+			}
+			if len(values)/int(a.attrib.Size) != count {
+				return out, fmt.Errorf("primitive %d %s has %d elements, POSITION has %d",
+					i, a.gltfName, len(values)/int(a.attrib.Size), count)
+			}
+			attribs[a.attrib.Name] = values
+		}
+
+		var indices []uint32
+		if prim.Indices != nil {
+			indices, err = l.indices(*prim.Indices)
+			if err != nil {
+				return out, fmt.Errorf("primitive %d indices: %v", i, err)
+			}
+		} else {
+			indices = make([]uint32, count)
+			for j := range indices {
+				indices[j] = uint32(j)
+			}
+		}
+
+		// Interleave into the shared buffer, rebasing indices past earlier primitives
+		base := uint32(len(out.Vertices) / format.Floats())
+		for v := 0; v < count; v++ {
+			for _, a := range format {
+				size := int(a.Size)
+				out.Vertices = append(out.Vertices, attribs[a.Name][v*size:(v+1)*size]...)
+			}
+		}
+
+		first := len(out.Indices)
+		for _, idx := range indices {
+			if int(idx) >= count {
+				return out, fmt.Errorf("primitive %d: index %d out of range", i, idx)
+			}
+			out.Indices = append(out.Indices, base+idx)
+		}
+
+		material := -1
+		if prim.Material != nil {
+			material = *prim.Material
+		}
+		out.Primitives = append(out.Primitives, Primitive{
+			First:    first,
+			Count:    len(indices),
+			Material: material,
+		})
+	}
+	return out, nil
+}
+
+// accessor reads a float accessor of the given type, converting normalized
+// integer components to floats
+func (l *gltfLoader) accessor(index int, accessorType string) ([]float32, error) {
+	a, data, stride, err := l.accessorData(index)
+	if err != nil {
+		return nil, err
+	}
+	if a.Type != accessorType {
+		return nil, fmt.Errorf("accessor %d is %s, expected %s", index, a.Type, accessorType)
+	}
+	if a.ComponentType != gltfFloat && !a.Normalized {
+		return nil, fmt.Errorf("accessor %d has unnormalized component type %d", index, a.ComponentType)
+	}
+
+	components := accessorComponents(a.Type)
+	size := componentSize(a.ComponentType)
+	out := make([]float32, 0, a.Count*components)
+	for i := 0; i < a.Count; i++ {
+		elem := data[i*stride:]
+		for c := 0; c < components; c++ {
+			out = append(out, readComponent(elem[c*size:], a.ComponentType))
+		}
+	}
+	return out, nil
+}
+
+func (l *gltfLoader) indices(index int) ([]uint32, error) {
+	a, data, stride, err := l.accessorData(index)
+	if err != nil {
+		return nil, err
+	}
+	if a.Type != "SCALAR" {
+		return nil, fmt.Errorf("index accessor %d is %s", index, a.Type)
+	}
+
+	out := make([]uint32, a.Count)
+	for i := range out {
+		elem := data[i*stride:]
+		switch a.ComponentType {
+		case gltfUnsignedByte:
+			out[i] = uint32(elem[0])
+		case gltfUnsignedShort:
+			out[i] = uint32(binary.LittleEndian.Uint16(elem))
+		case gltfUnsignedInt:
+			out[i] = binary.LittleEndian.Uint32(elem)
+		default:
+			return nil, fmt.Errorf("index accessor %d has component type %d", index, a.ComponentType)
+		}
+	}
+	return out, nil
+}
+
+// accessorData returns an accessor, the bytes starting at its first element and
+// the stride between elements, having checked every element lies in bounds
+func (l *gltfLoader) accessorData(index int) (gltfAccessor, []byte, int, error) {
+	if index < 0 || index >= len(l.doc.Accessors) {
+		return gltfAccessor{}, nil, 0, fmt.Errorf("accessor %d out of range", index)
+	}
+	a := l.doc.Accessors[index]
+	if len(a.Sparse) > 0 {
+		return a, nil, 0, fmt.Errorf("sparse accessor %d is not supported", index)
+	}
+
+	components, size := accessorComponents(a.Type), componentSize(a.ComponentType)
+	if components == 0 || size == 0 {
+		return a, nil, 0, fmt.Errorf("accessor %d has unknown type %s/%d", index, a.Type, a.ComponentType)
+	}
+	elemSize := components * size
+	if a.Count < 1 {
+		return a, nil, 0, fmt.Errorf("accessor %d has a count of %d", index, a.Count)
+	}
+	if a.ByteOffset < 0 {
+		return a, nil, 0, fmt.Errorf("accessor %d has a negative offset", index)
+	}
+
+	// Accessors without a buffer view are all zeros. Nothing bounds their
+	// count, so cap it before allocating.
+	if a.BufferView == nil {
+		if a.Count > maxZeroAccessorBytes/elemSize {
+			return a, nil, 0, fmt.Errorf("accessor %d has no buffer view and %d elements, too many to zero fill", index, a.Count)
+		}
+		return a, make([]byte, a.Count*elemSize), elemSize, nil
+	}
+
+	view, err := l.bufferView(*a.BufferView)
+	if err != nil {
+		return a, nil, 0, err
+	}
+	stride := l.doc.BufferViews[*a.BufferView].ByteStride
+	if stride == 0 {
+		stride = elemSize
+	} else if stride < elemSize {
+		return a, nil, 0, fmt.Errorf("accessor %d has %d byte elements but a stride of %d", index, elemSize, stride)
+	}
+
+	// Checked a step at a time so huge counts cannot overflow
+	room := len(view) - a.ByteOffset - elemSize
+	if a.ByteOffset > len(view) || room < 0 || (a.Count-1) > room/stride {
+		return a, nil, 0, fmt.Errorf("accessor %d overruns its buffer view", index)
+	}
+	return a, view[a.ByteOffset:], stride, nil
+}
+
+func accessorComponents(t string) int {
+	switch t {
+	case "SCALAR":
+		return 1
+	case "VEC2":
+		return 2
+	case "VEC3":
+		return 3
+	case "VEC4", "MAT2":
+		return 4
+	case "MAT3":
+		return 9
+	case "MAT4":
+		return 16
+	default:
+		return 0
+	}
+}
+
+func componentSize(componentType int) int {
+	switch componentType {
+	case gltfByte, gltfUnsignedByte:
+		return 1
+	case gltfShort, gltfUnsignedShort:
+		return 2
+	case gltfUnsignedInt, gltfFloat:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// readComponent decodes one component, mapping normalized integers to [0,1] or [-1,1]
+func readComponent(b []byte, componentType int) float32 {
+	switch componentType {
+	case gltfFloat:
+		return math.Float32frombits(binary.LittleEndian.Uint32(b))
+	case gltfUnsignedByte:
+		return float32(b[0]) / 255
+	case gltfByte:
+		return float32(math.Max(float64(int8(b[0]))/127, -1))
+	case gltfUnsignedShort:
+		return float32(binary.LittleEndian.Uint16(b)) / 65535
+	case gltfShort:
+		return float32(math.Max(float64(int16(binary.LittleEndian.Uint16(b)))/32767, -1))
+	default:
+		return 0
+	}
+}
+
+// Scene is a glTF asset uploaded to the GPU, ready to draw
+type Scene struct {
+	Models []*Model
+	Nodes  []Node
+
+	textures []uint32
+}
+
+// Upload creates GL meshes for every glTF mesh and textures for every image.
+// Each primitive becomes a part coloured and textured by its base color.
+func (g *GLTF) Upload() (*Scene, error) {
+	s := &Scene{Nodes: g.Nodes}
+
+	for _, img := range g.Images {
+		s.textures = append(s.textures, glutil.NewTexture(img))
+	}
+
+	for _, mesh := range g.Meshes {
+		glMesh, err := glutil.NewMesh(g.Format, mesh.Vertices, mesh.Indices)
+		if err != nil {
+			s.Delete()
+			return nil, fmt.Errorf("mesh %s: %v", mesh.Name, err)
+		}
+
+		m := &Model{Mesh: glMesh}
+		m.Min, m.Max = bounds(g.Format, mesh.Vertices)
+		for _, prim := range mesh.Primitives {
+			part := Part{
+				Name:  mesh.Name,
+				First: int32(prim.First),
+				Count: int32(prim.Count),
+				Color: mgl32.Vec4{1, 1, 1, 1},
+			}
+			if prim.Material >= 0 && prim.Material < len(g.Materials) {
+				mat := g.Materials[prim.Material]
+				part.Color = mat.BaseColor
+				if mat.BaseColorTexture >= 0 {
+					part.Texture = s.textures[mat.BaseColorTexture]
+				}
+			}
+			m.Parts = append(m.Parts, part)
+		}
+		s.Models = append(s.Models, m)
+	}
+	return s, nil
+}
+
+// Draw draws every node, setting the named mat4 uniform to parent times the
+// node's world transform before each one
+func (s *Scene) Draw(p *glutil.Program, modelUniform string, parent mgl32.Mat4) {
+	for _, n := range s.Nodes {
+		p.SetMat4(modelUniform, parent.Mul4(n.World))
+		s.Models[n.Mesh].Draw(p)
+	}
+}
+
+// Bounds is the world space axis aligned box around every node's model
+func (s *Scene) Bounds() (min, max mgl32.Vec3) {
+	first := true
+	for _, n := range s.Nodes {
+		m := s.Models[n.Mesh]
+		for c := 0; c < 8; c++ {
+			corner := m.Min
+			for j := 0; j < 3; j++ {
+				if c&(1<<uint(j)) != 0 {
+					corner[j] = m.Max[j]
+				}
+			}
+			p := mgl32.TransformCoordinate(corner, n.World)
+			if first {
+				min, max, first = p, p, false
+				continue
+			}
+			for j := 0; j < 3; j++ {
+				if p[j] < min[j] {
+					min[j] = p[j]
+				}
+				if p[j] > max[j] {
+					max[j] = p[j]
+				}
+			}
+		}
+	}
+	return
+}
+
+// Delete releases every mesh and texture in the scene
+func (s *Scene) Delete() {
+	for _, m := range s.Models {
+		m.Delete()
+	}
+	if len(s.textures) > 0 {
+		gl.DeleteTextures(int32(len(s.textures)), &s.textures[0])
+	}
+	s.Models = nil
+	s.textures = nil
+}
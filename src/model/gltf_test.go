@@ -0,0 +1,409 @@
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"glutil"
+)
+
+// triangleBuffer holds the positions (0,0,0), (1,0,0) and (0,1,0)
+func triangleBuffer() []byte {
+	buf := make([]byte, 36)
+	for i, f := range []float32{0, 0, 0, 1, 0, 0, 0, 1, 0} {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(f))
+	}
+	return buf
+}
+
+func dataURI(mime string, data []byte) string {
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// triangleGLTF is a one triangle glTF whose buffer view and accessor fields
+// can be replaced to make it malformed. The accessor's fields include its
+// bufferView, so it can be left without one.
+func triangleGLTF(view, accessor string) string {
+	if view == "" {
+		view = `"byteOffset": 0, "byteLength": 36`
+	}
+	if accessor == "" {
+		accessor = `"bufferView": 0, "byteOffset": 0, "count": 3`
+	}
+	return fmt.Sprintf(`{
+		"asset": {"version": "2.0"},
+		"scenes": [{"nodes": [0]}],
+		"nodes": [{"mesh": 0}],
+		"meshes": [{"primitives": [{"attributes": {"POSITION": 0}}]}],
+		"accessors": [{"componentType": 5126, "type": "VEC3", %s}],
+		"bufferViews": [{"buffer": 0, %s}],
+		"buffers": [{"uri": "%s", "byteLength": 36}]
+	}`, accessor, view, dataURI("application/octet-stream", triangleBuffer()))
+}
+
+// gltfDocument is a glTF with the triangle as mesh 0 plus the given top level
+// members, which must include buffers
+func gltfDocument(members ...string) string {
+	return `{
+		"asset": {"version": "2.0"},
+		"meshes": [{"primitives": [{"attributes": {"POSITION": 0}}]}],
+		"accessors": [{"bufferView": 0, "componentType": 5126, "type": "VEC3", "count": 3}],
+		"bufferViews": [{"buffer": 0, "byteLength": 36}],
+		` + strings.Join(members, ",\n") + `
+	}`
+}
+
+// triangleBuffers is the buffers member holding the triangle as a data URI
+func triangleBuffers() string {
+	return fmt.Sprintf(`"buffers": [{"uri": "%s", "byteLength": 36}]`, dataURI("application/octet-stream", triangleBuffer()))
+}
+
+// glb packs JSON and binary chunks into a GLB container, padding them to 4
+// bytes as the format requires. Empty chunks are left out.
+func glb(doc string, bin []byte) string {
+	var chunks bytes.Buffer
+	chunk := func(kind uint32, data []byte, pad byte) {
+		for len(data)%4 != 0 {
+			data = append(data, pad)
+		}
+		binary.Write(&chunks, binary.LittleEndian, uint32(len(data)))
+		binary.Write(&chunks, binary.LittleEndian, kind)
+		chunks.Write(data)
+	}
+	if doc != "" {
+		chunk(glbChunkJSON, []byte(doc), ' ')
+	}
+	if bin != nil {
+		chunk(glbChunkBIN, append([]byte(nil), bin...), 0)
+	}
+
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, uint32(glbMagic))
+	binary.Write(&out, binary.LittleEndian, uint32(2))
+	binary.Write(&out, binary.LittleEndian, uint32(12+chunks.Len()))
+	out.Write(chunks.Bytes())
+	return out.String()
+}
+
+// redPNG is a 1x1 red image
+func redPNG(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func loadTestGLTF(t *testing.T, files map[string]string, name string, format glutil.VertexFormat) (*GLTF, error) {
+	t.Helper()
+	useAssets(t, files)
+	return LoadGLTF(name, format)
+}
+
+// transformed applies a node's world transform to a point
+func transformed(n Node, p mgl32.Vec3) mgl32.Vec3 {
+	return mgl32.TransformCoordinate(p, n.World)
+}
+
+func approxVec3(a, b mgl32.Vec3) bool {
+	return a.ApproxEqualThreshold(b, 1e-5)
+}
+
+func TestLoadGLTF(t *testing.T) {
+	positions := glutil.NewVertexFormat(glutil.Position3f)
+	sin45 := float32(math.Sqrt2 / 2)
+
+	tests := []struct {
+		name   string
+		files  map[string]string
+		load   string
+		format glutil.VertexFormat
+		check  func(t *testing.T, g *GLTF)
+	}{
+		{
+			name:  "data uri",
+			files: map[string]string{"test.gltf": triangleGLTF("", "")},
+			load:  "test.gltf",
+			check: func(t *testing.T, g *GLTF) {
+				want := []float32{0, 0, 0, 1, 0, 0, 0, 1, 0}
+				if fmt.Sprint(g.Meshes[0].Vertices) != fmt.Sprint(want) {
+					t.Errorf("vertices %v, want %v", g.Meshes[0].Vertices, want)
+				}
+				if fmt.Sprint(g.Meshes[0].Indices) != "[0 1 2]" {
+					t.Errorf("indices %v, want [0 1 2]", g.Meshes[0].Indices)
+				}
+			},
+		},
+		{
+			name: "external buffer next to the file",
+			files: map[string]string{
+				"models/test.gltf": gltfDocument(`"nodes": [{"mesh": 0}]`, `"buffers": [{"uri": "tri.bin", "byteLength": 36}]`),
+				"models/tri.bin":   string(triangleBuffer()),
+			},
+			load: "models/test.gltf",
+			check: func(t *testing.T, g *GLTF) {
+				if v := g.Meshes[0].Vertices; len(v) != 9 || v[3] != 1 || v[7] != 1 {
+					t.Errorf("vertices %v", v)
+				}
+			},
+		},
+		{
+			name: "glb binary chunk",
+			files: map[string]string{
+				"test.glb": glb(gltfDocument(`"nodes": [{"mesh": 0}]`, `"buffers": [{"byteLength": 36}]`), triangleBuffer()),
+			},
+			load: "test.glb",
+			check: func(t *testing.T, g *GLTF) {
+				if v := g.Meshes[0].Vertices; len(v) != 9 || v[3] != 1 || v[7] != 1 {
+					t.Errorf("vertices %v", v)
+				}
+				if len(g.Nodes) != 1 {
+					t.Errorf("got %d nodes, want 1", len(g.Nodes))
+				}
+			},
+		},
+		{
+			name: "glb with json only",
+			files: map[string]string{
+				"test.glb": glb(gltfDocument(`"nodes": [{"mesh": 0}]`, triangleBuffers()), nil),
+			},
+			load: "test.glb",
+			check: func(t *testing.T, g *GLTF) {
+				if len(g.Meshes[0].Vertices) != 9 {
+					t.Errorf("vertices %v", g.Meshes[0].Vertices)
+				}
+			},
+		},
+		{
+			name: "trs hierarchy",
+			files: map[string]string{"test.gltf": gltfDocument(
+				`"scenes": [{"nodes": [0]}]`,
+				fmt.Sprintf(`"nodes": [
+					{"children": [1], "translation": [1, 2, 3]},
+					{"mesh": 0, "rotation": [0, %v, 0, %v], "scale": [2, 2, 2]}
+				]`, sin45, sin45),
+				triangleBuffers(),
+			)},
+			load: "test.gltf",
+			check: func(t *testing.T, g *GLTF) {
+				if len(g.Nodes) != 1 {
+					t.Fatalf("got %d nodes, want only the one with a mesh", len(g.Nodes))
+				}
+				// Scaled to (2,0,0), turned 90 degrees about Y to (0,0,-2),
+				// then moved by the parent
+				if p := transformed(g.Nodes[0], mgl32.Vec3{1, 0, 0}); !approxVec3(p, mgl32.Vec3{1, 2, 1}) {
+					t.Errorf("(1,0,0) -> %v, want (1,2,1)", p)
+				}
+			},
+		},
+		{
+			name: "matrix parent",
+			files: map[string]string{"test.gltf": gltfDocument(
+				`"scenes": [{"nodes": [0]}]`,
+				`"nodes": [
+					{"children": [1], "matrix": [1,0,0,0, 0,1,0,0, 0,0,1,0, 5,6,7,1]},
+					{"mesh": 0, "translation": [1, 0, 0]}
+				]`,
+				triangleBuffers(),
+			)},
+			load: "test.gltf",
+			check: func(t *testing.T, g *GLTF) {
+				if p := transformed(g.Nodes[0], mgl32.Vec3{}); !approxVec3(p, mgl32.Vec3{6, 6, 7}) {
+					t.Errorf("origin -> %v, want (6,6,7)", p)
+				}
+			},
+		},
+		{
+			name: "roots without scenes",
+			files: map[string]string{"test.gltf": gltfDocument(
+				`"nodes": [
+					{"mesh": 0, "translation": [1, 0, 0]},
+					{"children": [0]},
+					{"mesh": 0, "translation": [0, 0, 4]}
+				]`,
+				triangleBuffers(),
+			)},
+			load: "test.gltf",
+			check: func(t *testing.T, g *GLTF) {
+				if len(g.Nodes) != 2 {
+					t.Fatalf("got %d nodes, want 2", len(g.Nodes))
+				}
+				got := []mgl32.Vec3{transformed(g.Nodes[0], mgl32.Vec3{}), transformed(g.Nodes[1], mgl32.Vec3{})}
+				if !approxVec3(got[0], mgl32.Vec3{1, 0, 0}) || !approxVec3(got[1], mgl32.Vec3{0, 0, 4}) {
+					t.Errorf("node origins %v, want (1,0,0) and (0,0,4)", got)
+				}
+			},
+		},
+		{
+			name: "materials",
+			files: map[string]string{"test.gltf": `{
+				"asset": {"version": "2.0"},
+				"nodes": [{"mesh": 0}],
+				"meshes": [{"primitives": [
+					{"attributes": {"POSITION": 0}, "material": 0},
+					{"attributes": {"POSITION": 0}, "material": 1},
+					{"attributes": {"POSITION": 0}}
+				]}],
+				"accessors": [{"bufferView": 0, "componentType": 5126, "type": "VEC3", "count": 3}],
+				"bufferViews": [{"buffer": 0, "byteLength": 36}],
+				"materials": [
+					{"name": "tinted", "pbrMetallicRoughness": {
+						"baseColorFactor": [0.5, 0.25, 1, 0.75],
+						"baseColorTexture": {"index": 0},
+						"metallicFactor": 0,
+						"roughnessFactor": 0.5
+					}, "alphaMode": "BLEND", "doubleSided": true},
+					{"name": "plain"}
+				],
+				"textures": [{"source": 0}],
+				"images": [{"uri": "` + dataURI("image/png", redPNG(t)) + `"}],
+				` + triangleBuffers() + `
+			}`},
+			load: "test.gltf",
+			check: func(t *testing.T, g *GLTF) {
+				want := []PBRMaterial{
+					{Name: "tinted", BaseColor: mgl32.Vec4{0.5, 0.25, 1, 0.75}, BaseColorTexture: 0,
+						Metallic: 0, Roughness: 0.5, MetallicRoughnessTexture: -1, AlphaMode: "BLEND", DoubleSided: true},
+					{Name: "plain", BaseColor: mgl32.Vec4{1, 1, 1, 1}, BaseColorTexture: -1,
+						Metallic: 1, Roughness: 1, MetallicRoughnessTexture: -1, AlphaMode: "OPAQUE"},
+				}
+				if len(g.Materials) != len(want) {
+					t.Fatalf("got %d materials, want %d", len(g.Materials), len(want))
+				}
+				for i := range want {
+					if g.Materials[i] != want[i] {
+						t.Errorf("material %d = %+v, want %+v", i, g.Materials[i], want[i])
+					}
+				}
+
+				if len(g.Images) != 1 || g.Images[0].RGBAAt(0, 0) != (color.RGBA{255, 0, 0, 255}) {
+					t.Errorf("images %v, want one red pixel", g.Images)
+				}
+
+				prims := g.Meshes[0].Primitives
+				for i, want := range []Primitive{{0, 3, 0}, {3, 3, 1}, {6, 3, -1}} {
+					if prims[i] != want {
+						t.Errorf("primitive %d = %+v, want %+v", i, prims[i], want)
+					}
+				}
+				if idx := g.Meshes[0].Indices; fmt.Sprint(idx) != "[0 1 2 3 4 5 6 7 8]" {
+					t.Errorf("indices %v, want each primitive rebased", idx)
+				}
+			},
+		},
+		{
+			name: "accessor without a buffer view",
+			files: map[string]string{"test.gltf": `{
+				"asset": {"version": "2.0"},
+				"nodes": [{"mesh": 0}],
+				"meshes": [{"primitives": [{"attributes": {"POSITION": 0, "NORMAL": 1}}]}],
+				"accessors": [
+					{"bufferView": 0, "componentType": 5126, "type": "VEC3", "count": 3},
+					{"componentType": 5126, "type": "VEC3", "count": 3}
+				],
+				"bufferViews": [{"buffer": 0, "byteLength": 36}],
+				` + triangleBuffers() + `
+			}`},
+			load:   "test.gltf",
+			format: glutil.NewVertexFormat(glutil.Position3f, glutil.Normal3f),
+			check: func(t *testing.T, g *GLTF) {
+				want := []float32{0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0}
+				if fmt.Sprint(g.Meshes[0].Vertices) != fmt.Sprint(want) {
+					t.Errorf("vertices %v, want zero normals %v", g.Meshes[0].Vertices, want)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format := tt.format
+			if format == nil {
+				format = positions
+			}
+			g, err := loadTestGLTF(t, tt.files, tt.load, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(g.Meshes) != 1 {
+				t.Fatalf("got %d meshes, want 1", len(g.Meshes))
+			}
+			tt.check(t, g)
+		})
+	}
+}
+
+func TestLoadGLTFRejectsBadLayout(t *testing.T) {
+	tests := []struct {
+		name     string
+		view     string
+		accessor string
+		want     string
+	}{
+		{"negative view offset", `"byteOffset": -4, "byteLength": 36`, "", "negative"},
+		{"negative view length", `"byteOffset": 0, "byteLength": -1`, "", "negative"},
+		{"negative stride", `"byteLength": 36, "byteStride": -12`, "", "negative"},
+		{"stride below element size", `"byteLength": 36, "byteStride": 4`, "", "stride"},
+		{"view past buffer", `"byteOffset": 4, "byteLength": 36`, "", "overruns"},
+		{"negative accessor offset", "", `"bufferView": 0, "byteOffset": -4, "count": 3`, "negative"},
+		{"negative count", "", `"bufferView": 0, "count": -1`, "count of -1"},
+		{"zero count", "", `"bufferView": 0, "count": 0`, "count of 0"},
+		{"accessor past view", "", `"bufferView": 0, "byteOffset": 4, "count": 3`, "overruns"},
+		{"huge count", "", `"bufferView": 0, "count": 4611686018427387904`, "overruns"},
+		{"huge count without view", "", `"count": 4611686018427387904`, "too many"},
+		{"large count without view", "", `"count": 100000000`, "too many"},
+		{"sparse", "", `"count": 3, "sparse": {"count": 1}`, "sparse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"test.gltf": triangleGLTF(tt.view, tt.accessor)}
+			_, err := loadTestGLTF(t, files, "test.gltf", glutil.NewVertexFormat(glutil.Position3f))
+			if err == nil {
+				t.Fatal("loaded without error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadGLBRejectsBadContainer(t *testing.T) {
+	doc := gltfDocument(`"nodes": [{"mesh": 0}]`, `"buffers": [{"byteLength": 36}]`)
+	good := glb(doc, triangleBuffer())
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"truncated header", good[:16], "truncated GLB header"},
+		{"wrong version", good[:4] + "\x01\x00\x00\x00" + good[8:], "version 1"},
+		{"length past end", good[:len(good)-4], "truncated GLB"},
+		{"no binary chunk", glb(doc, nil), "no GLB binary chunk"},
+		{"no json chunk", glb("", triangleBuffer()), "no JSON chunk"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadTestGLTF(t, map[string]string{"test.glb": tt.data}, "test.glb", glutil.NewVertexFormat(glutil.Position3f))
+			if err == nil {
+				t.Fatal("loaded without error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not mention %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Package model loads meshes from model files into drawable glutil meshes
+package model
+
+import (
+	"fmt"
+	"image"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"glutil"
+)
+
+// Part is a range of a model's index buffer drawn with one material
+type Part struct {
+	Name  string
+	First int32
+	Count int32
+
+	// Texture is bound to unit 0 while drawing the part; 0 means untextured,
+	// which binds a white texture so Color shows through
+	Texture uint32
+
+	// Color is the material's base color, multiplied in through ColorUniform
+	Color mgl32.Vec4
+}
+
+// ColorUniform is the vec4 uniform Draw sets to each part's Color. Programs
+// without it draw every part with its texture alone. Shaders should give it
+// a default of vec4(1.0) so other geometry drawn with them is unaffected.
+const ColorUniform = "baseColor"
+
+// Model is a mesh on the GPU split into parts by material
+type Model struct {
+	Mesh  *glutil.Mesh
+	Parts []Part
+
+	// Min and Max bound the mesh's positions in model space
+	Min, Max mgl32.Vec3
+
+	textures []uint32
+
+	// white is a 1x1 white texture standing in for untextured parts, made on
+	// first use in the context the model was uploaded to
+	white uint32
+}
+
+func (m *Model) whiteTexture() uint32 {
+	if m.white == 0 {
+		img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+		img.Pix = []uint8{255, 255, 255, 255}
+		m.white = glutil.NewTexture(img)
+	}
+	return m.white
+}
+
+// Draw draws every part with p, binding its diffuse texture to texture unit
+// 0 and setting ColorUniform to its color. The texture previously bound to
+// unit 0 is restored afterwards and ColorUniform is left white.
+func (m *Model) Draw(p *glutil.Program) {
+	var prevUnit, prevTexture int32
+	gl.GetIntegerv(gl.ACTIVE_TEXTURE, &prevUnit)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.GetIntegerv(gl.TEXTURE_BINDING_2D, &prevTexture)
+
+	colored := p.UniformLocation(ColorUniform) >= 0
+	for _, part := range m.Parts {
+		texture := part.Texture
+		if texture == 0 {
+			texture = m.whiteTexture()
+		}
+		gl.BindTexture(gl.TEXTURE_2D, texture)
+		if colored {
+			p.SetVec4(ColorUniform, part.Color)
+		}
+		m.Mesh.DrawRange(part.First, part.Count)
+	}
+
+	if colored {
+		p.SetVec4(ColorUniform, mgl32.Vec4{1, 1, 1, 1})
+	}
+	gl.BindTexture(gl.TEXTURE_2D, uint32(prevTexture))
+	gl.ActiveTexture(uint32(prevUnit))
+}
+
+// Delete releases the mesh and every texture loaded for the model
+func (m *Model) Delete() {
+	m.Mesh.Delete()
+	if len(m.textures) > 0 {
+		gl.DeleteTextures(int32(len(m.textures)), &m.textures[0])
+	}
+	m.textures = nil
+	if m.white != 0 {
+		gl.DeleteTextures(1, &m.white)
+		m.white = 0
+	}
+}
+
+// bounds is the axis aligned box around the positions in vertex data
+func bounds(format glutil.VertexFormat, vertices []float32) (min, max mgl32.Vec3) {
+	loc := format.Location(glutil.Position3f.Name)
+	floats := format.Floats()
+	if loc < 0 || len(vertices) < floats {
+		return
+	}
+	offset := format.Offset(loc) / 4
+
+	min = mgl32.Vec3{vertices[offset], vertices[offset+1], vertices[offset+2]}
+	max = min
+	for i := offset; i+2 < len(vertices); i += floats {
+		for j := 0; j < 3; j++ {
+			if v := vertices[i+j]; v < min[j] {
+				min[j] = v
+			} else if v > max[j] {
+				max[j] = v
+			}
+		}
+	}
+	return
+}
+
+// Load reads a .gltf, .glb or .obj file and uploads it as a Scene. OBJ files
+// become a single node at the origin. Files and everything they reference
+// are read through glutil.OpenAsset.
+func Load(filename string, format glutil.VertexFormat) (*Scene, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".gltf", ".glb":
+		g, err := LoadGLTF(filename, format)
+		if err != nil {
+			return nil, err
+		}
+		return g.Upload()
+
+	case ".obj":
+		o, err := LoadOBJ(filename, format)
+		if err != nil {
+			return nil, err
+		}
+		m, err := o.Upload()
+		if err != nil {
+			return nil, err
+		}
+		return &Scene{
+			Models: []*Model{m},
+			Nodes:  []Node{{Name: filename, Mesh: 0, World: mgl32.Ident4()}},
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown model format: %s", filename)
+	}
+}
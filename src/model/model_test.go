@@ -0,0 +1,20 @@
+package model
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"glutil"
+)
+
+// useAssets serves files from memory as glutil.Assets for the rest of a test
+func useAssets(t *testing.T, files map[string]string) {
+	t.Helper()
+	fsys := make(fstest.MapFS)
+	for name, data := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(data)}
+	}
+	prev := glutil.Assets
+	glutil.Assets = fsys
+	t.Cleanup(func() { glutil.Assets = prev })
+}
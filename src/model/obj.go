@@ -0,0 +1,439 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"glutil"
+)
+
+// Material is the part of a Wavefront MTL material the tutorials can use
+type Material struct {
+	Name       string
+	Ambient    mgl32.Vec3
+	Diffuse    mgl32.Vec3
+	Specular   mgl32.Vec3
+	Shininess  float32
+	Opacity    float32
+	DiffuseMap string
+}
+
+// Group is a run of faces in an OBJ file sharing a group name and material
+type Group struct {
+	Name     string
+	Material string
+	First    int
+	Count    int
+}
+
+// OBJ is a Wavefront OBJ file flattened to deduplicated, indexed vertex data
+type OBJ struct {
+	Format    glutil.VertexFormat
+	Vertices  []float32
+	Indices   []uint32
+	Groups    []Group
+	Materials map[string]*Material
+}
+
+// objIndex is a face vertex's position/uv/normal index triple
+type objIndex struct {
+	v, vt, vn int
+}
+
+// vertexKey identifies a unique output vertex. Vertices using a generated flat
+// normal also key on it so they are not shared between faces facing different ways.
+type vertexKey struct {
+	objIndex
+	flat int
+}
+
+type objParser struct {
+	filename string
+	format   glutil.VertexFormat
+
+	positions []mgl32.Vec3
+	uvs       []mgl32.Vec2
+	normals   []mgl32.Vec3
+	flats     map[mgl32.Vec3]int
+
+	obj     *OBJ
+	vertex  map[vertexKey]uint32
+	group   string
+	current string
+}
+
+// LoadOBJ reads an OBJ file and any MTL libraries it references. Vertices are
+// emitted in the given format, which may contain Position3f, UV2f and Normal3f.
+// Polygons are triangulated as fans and faces without normals get flat ones.
+func LoadOBJ(filename string, format glutil.VertexFormat) (*OBJ, error) {
+	for _, a := range format {
+		if a != glutil.Position3f && a != glutil.UV2f && a != glutil.Normal3f {
+			return nil, fmt.Errorf("OBJ files cannot provide vertex attribute %s", a.Name)
+		}
+	}
+
+	f, err := glutil.OpenAsset(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p := &objParser{
+		filename: filename,
+		format:   format,
+		obj: &OBJ{
+			Format:    format,
+			Materials: make(map[string]*Material),
+		},
+		vertex: make(map[vertexKey]uint32),
+		flats:  make(map[mgl32.Vec3]int),
+	}
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if err := p.parseLine(scanner.Text()); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", filename, lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	p.endGroup()
+	fmt.Printf("Loaded %s: %d vertices, %d triangles\n", filename, len(p.obj.Vertices)/format.Floats(), len(p.obj.Indices)/3)
+	return p.obj, nil
+}
+
+func (p *objParser) parseLine(line string) error {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	switch fields[0] {
+	case "v":
+		v, err := parseFloats(fields[1:], 3)
+		if err != nil {
+			return err
+		}
+		p.positions = append(p.positions, mgl32.Vec3{v[0], v[1], v[2]})
+
+	case "vt":
+		v, err := parseFloats(fields[1:], 2)
+		if err != nil {
+			return err
+		}
+		p.uvs = append(p.uvs, mgl32.Vec2{v[0], v[1]})
+
+	case "vn":
+		v, err := parseFloats(fields[1:], 3)
+		if err != nil {
+			return err
+		}
+		p.normals = append(p.normals, mgl32.Vec3{v[0], v[1], v[2]})
+
+	case "f":
+		return p.parseFace(fields[1:])
+
+	case "g", "o":
+		p.endGroup()
+		p.group = strings.Join(fields[1:], " ")
+
+	case "usemtl":
+		p.endGroup()
+		if len(fields) > 1 {
+			p.current = fields[1]
+		}
+
+	case "mtllib":
+		for _, name := range fields[1:] {
+			path := filepath.Join(filepath.Dir(p.filename), name)
+			if err := loadMTL(path, p.obj.Materials); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Smoothing groups, lines, points and anything else are ignored
+	return nil
+}
+
+// endGroup closes the current run of faces so the next one starts a new group
+func (p *objParser) endGroup() {
+	first := 0
+	if n := len(p.obj.Groups); n > 0 {
+		last := p.obj.Groups[n-1]
+		first = last.First + last.Count
+	}
+
+	if count := len(p.obj.Indices) - first; count > 0 {
+		p.obj.Groups = append(p.obj.Groups, Group{
+			Name:     p.group,
+			Material: p.current,
+			First:    first,
+			Count:    count,
+		})
+	}
+}
+
+func (p *objParser) parseFace(refs []string) error {
+	if len(refs) < 3 {
+		return fmt.Errorf("face with %d vertices", len(refs))
+	}
+
+	face := make([]objIndex, len(refs))
+	for i, ref := range refs {
+		idx, err := p.parseRef(ref)
+		if err != nil {
+			return err
+		}
+		face[i] = idx
+	}
+
+	// Vertices without normals get the normal of the face's plane
+	var flat mgl32.Vec3
+	if missingNormal(face) {
+		a, b, c := p.positions[face[0].v], p.positions[face[1].v], p.positions[face[2].v]
+		if n := b.Sub(a).Cross(c.Sub(a)); n.Len() > 0 {
+			flat = n.Normalize()
+		}
+	}
+
+	// Triangulate as a fan around the first vertex
+	for i := 1; i+1 < len(face); i++ {
+		for _, idx := range []objIndex{face[0], face[i], face[i+1]} {
+			p.obj.Indices = append(p.obj.Indices, p.emit(idx, flat))
+		}
+	}
+	return nil
+}
+
+func missingNormal(face []objIndex) bool {
+	for _, idx := range face {
+		if idx.vn < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRef resolves a v, v/vt, v//vn or v/vt/vn reference to 0-based indices,
+// with -1 for missing components. Negative OBJ indices count back from the end.
+func (p *objParser) parseRef(ref string) (objIndex, error) {
+	idx := objIndex{-1, -1, -1}
+	parts := strings.Split(ref, "/")
+	counts := []int{len(p.positions), len(p.uvs), len(p.normals)}
+	out := []*int{&idx.v, &idx.vt, &idx.vn}
+
+	for i, part := range parts {
+		if i > 2 {
+			return idx, fmt.Errorf("malformed face vertex %q", ref)
+		}
+		if part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return idx, fmt.Errorf("malformed face vertex %q", ref)
+		}
+		if n < 0 {
+			n = counts[i] + n
+		} else {
+			n--
+		}
+		if n < 0 || n >= counts[i] {
+			return idx, fmt.Errorf("face vertex %q out of range", ref)
+		}
+		*out[i] = n
+	}
+
+	if idx.v < 0 {
+		return idx, fmt.Errorf("face vertex %q has no position", ref)
+	}
+	return idx, nil
+}
+
+// emit returns the index of the vertex for idx, appending it on first use
+func (p *objParser) emit(idx objIndex, flat mgl32.Vec3) uint32 {
+	key := vertexKey{objIndex: idx, flat: -1}
+	if idx.vn < 0 && p.format.Location(glutil.Normal3f.Name) >= 0 {
+		key.flat = p.flatIndex(flat)
+	}
+	if i, ok := p.vertex[key]; ok {
+		return i
+	}
+
+	i := uint32(len(p.obj.Vertices) / p.format.Floats())
+	for _, a := range p.format {
+		switch a {
+		case glutil.Position3f:
+			v := p.positions[idx.v]
+			p.obj.Vertices = append(p.obj.Vertices, v[0], v[1], v[2])
+		case glutil.UV2f:
+			var uv mgl32.Vec2
+			if idx.vt >= 0 {
+				uv = p.uvs[idx.vt]
+			}
+			// OBJ puts v=0 at the bottom, our images are uploaded top row first
+			p.obj.Vertices = append(p.obj.Vertices, uv[0], 1-uv[1])
+		case glutil.Normal3f:
+			n := flat
+			if idx.vn >= 0 {
+				n = p.normals[idx.vn]
+			}
+			p.obj.Vertices = append(p.obj.Vertices, n[0], n[1], n[2])
+		}
+	}
+
+	p.vertex[key] = i
+	return i
+}
+
+// flatIndex interns flat normals so identical ones share a dedup key
+func (p *objParser) flatIndex(n mgl32.Vec3) int {
+	i, ok := p.flats[n]
+	if !ok {
+		i = len(p.flats)
+		p.flats[n] = i
+	}
+	return i
+}
+
+func parseFloats(fields []string, n int) ([]float32, error) {
+	if len(fields) < n {
+		return nil, fmt.Errorf("expected %d values, got %d", n, len(fields))
+	}
+	out := make([]float32, n)
+	for i := range out {
+		f, err := strconv.ParseFloat(fields[i], 32)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = float32(f)
+	}
+	return out, nil
+}
+
+// loadMTL adds the materials defined in an MTL library to materials
+func loadMTL(filename string, materials map[string]*Material) error {
+	f, err := glutil.OpenAsset(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var m *Material
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[0] == "newmtl" {
+			if len(fields) < 2 {
+				return fmt.Errorf("%s:%d: newmtl without a name", filename, lineNo)
+			}
+			m = &Material{Name: fields[1], Diffuse: mgl32.Vec3{1, 1, 1}, Opacity: 1}
+			materials[m.Name] = m
+			continue
+		}
+		if m == nil {
+			continue
+		}
+
+		var err error
+		switch fields[0] {
+		case "Ka":
+			m.Ambient, err = parseVec3(fields[1:])
+		case "Kd":
+			m.Diffuse, err = parseVec3(fields[1:])
+		case "Ks":
+			m.Specular, err = parseVec3(fields[1:])
+		case "Ns":
+			var v []float32
+			if v, err = parseFloats(fields[1:], 1); err == nil {
+				m.Shininess = v[0]
+			}
+		case "d":
+			var v []float32
+			if v, err = parseFloats(fields[1:], 1); err == nil {
+				m.Opacity = v[0]
+			}
+		case "map_Kd":
+			// Options such as -s or -bm come before the file name
+			if len(fields) > 1 {
+				m.DiffuseMap = filepath.Join(filepath.Dir(filename), fields[len(fields)-1])
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s:%d: %v", filename, lineNo, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func parseVec3(fields []string) (mgl32.Vec3, error) {
+	v, err := parseFloats(fields, 3)
+	if err != nil {
+		return mgl32.Vec3{}, err
+	}
+	return mgl32.Vec3{v[0], v[1], v[2]}, nil
+}
+
+// Upload creates the GL mesh for the OBJ and loads each material's diffuse
+// texture, producing one part per group
+func (o *OBJ) Upload() (*Model, error) {
+	mesh, err := glutil.NewMesh(o.Format, o.Vertices, o.Indices)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Model{Mesh: mesh}
+	m.Min, m.Max = bounds(o.Format, o.Vertices)
+	textures := make(map[string]uint32)
+	for _, g := range o.Groups {
+		part := Part{
+			Name:  g.Name,
+			First: int32(g.First),
+			Count: int32(g.Count),
+			Color: mgl32.Vec4{1, 1, 1, 1},
+		}
+
+		if mat, ok := o.Materials[g.Material]; ok {
+			part.Color = mat.Diffuse.Vec4(mat.Opacity)
+			if mat.DiffuseMap != "" {
+				tex, ok := textures[mat.DiffuseMap]
+				if !ok {
+					tex, err = glutil.LoadTexture(mat.DiffuseMap)
+					if err != nil {
+						m.Delete()
+						return nil, err
+					}
+					textures[mat.DiffuseMap] = tex
+					m.textures = append(m.textures, tex)
+				}
+				part.Texture = tex
+			}
+		}
+
+		m.Parts = append(m.Parts, part)
+	}
+	return m, nil
+}
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"glutil"
+)
+
+func loadTestOBJ(t *testing.T, files map[string]string, format glutil.VertexFormat) *OBJ {
+	t.Helper()
+	useAssets(t, files)
+	o, err := LoadOBJ("test.obj", format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return o
+}
+
+// vertexAt returns the floats of output vertex i
+func vertexAt(o *OBJ, i uint32) []float32 {
+	n := uint32(o.Format.Floats())
+	return o.Vertices[i*n : (i+1)*n]
+}
+
+func TestLoadOBJTriangulatesFaces(t *testing.T) {
+	o := loadTestOBJ(t, map[string]string{"test.obj": `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+v 0 0 1
+f 1 2 3 4 5
+`}, glutil.NewVertexFormat(glutil.Position3f))
+
+	want := []uint32{0, 1, 2, 0, 2, 3, 0, 3, 4}
+	if len(o.Indices) != len(want) {
+		t.Fatalf("indices %v, want %v", o.Indices, want)
+	}
+	for i := range want {
+		if o.Indices[i] != want[i] {
+			t.Fatalf("indices %v, want %v", o.Indices, want)
+		}
+	}
+	if n := len(o.Vertices) / 3; n != 5 {
+		t.Errorf("got %d vertices, want 5", n)
+	}
+}
+
+func TestLoadOBJNegativeIndices(t *testing.T) {
+	o := loadTestOBJ(t, map[string]string{"test.obj": `
+v 9 9 9
+v 1 0 0
+v 0 1 0
+v 0 0 1
+vt 0.25 0.75
+f -3/-1 -2/-1 -1/-1
+`}, glutil.NewVertexFormat(glutil.Position3f, glutil.UV2f))
+
+	want := [][]float32{
+		{1, 0, 0, 0.25, 0.25},
+		{0, 1, 0, 0.25, 0.25},
+		{0, 0, 1, 0.25, 0.25},
+	}
+	for i, idx := range o.Indices {
+		got := vertexAt(o, idx)
+		for j := range want[i] {
+			if got[j] != want[i][j] {
+				t.Errorf("vertex %d = %v, want %v", i, got, want[i])
+				break
+			}
+		}
+	}
+}
+
+func TestLoadOBJFlatNormals(t *testing.T) {
+	// The first vertex of the face has an explicit normal, the others get
+	// the face normal rather than zero
+	o := loadTestOBJ(t, map[string]string{"test.obj": `
+v 0 0 0
+v 1 0 0
+v 0 1 0
+vn 1 0 0
+f 1//1 2 3
+`}, glutil.NewVertexFormat(glutil.Position3f, glutil.Normal3f))
+
+	want := []mgl32.Vec3{{1, 0, 0}, {0, 0, 1}, {0, 0, 1}}
+	for i, idx := range o.Indices {
+		v := vertexAt(o, idx)
+		if n := (mgl32.Vec3{v[3], v[4], v[5]}); n != want[i] {
+			t.Errorf("vertex %d normal = %v, want %v", i, n, want[i])
+		}
+	}
+}
+
+func TestLoadOBJMaterials(t *testing.T) {
+	o := loadTestOBJ(t, map[string]string{
+		"test.obj": `
+mtllib test.mtl
+v 0 0 0
+v 1 0 0
+v 0 1 0
+g red
+usemtl red
+f 1 2 3
+g plain
+usemtl textured
+f 3 2 1
+`,
+		"test.mtl": `
+# two materials
+newmtl red
+Kd 0.8 0 0
+Ks 0.5 0.5 0.5
+Ns 32
+d 0.5
+
+newmtl textured
+map_Kd -s 1 1 1 wood.png
+`,
+	}, glutil.NewVertexFormat(glutil.Position3f))
+
+	red, ok := o.Materials["red"]
+	if !ok {
+		t.Fatal("material red not loaded")
+	}
+	if red.Diffuse != (mgl32.Vec3{0.8, 0, 0}) || red.Specular != (mgl32.Vec3{0.5, 0.5, 0.5}) {
+		t.Errorf("red colors = %v %v", red.Diffuse, red.Specular)
+	}
+	if red.Shininess != 32 || red.Opacity != 0.5 {
+		t.Errorf("red shininess %v opacity %v, want 32 0.5", red.Shininess, red.Opacity)
+	}
+
+	textured, ok := o.Materials["textured"]
+	if !ok {
+		t.Fatal("material textured not loaded")
+	}
+	if textured.DiffuseMap != "wood.png" || textured.Diffuse != (mgl32.Vec3{1, 1, 1}) || textured.Opacity != 1 {
+		t.Errorf("textured = %+v", textured)
+	}
+
+	want := []Group{
+		{Name: "red", Material: "red", First: 0, Count: 3},
+		{Name: "plain", Material: "textured", First: 3, Count: 3},
+	}
+	if len(o.Groups) != len(want) {
+		t.Fatalf("groups %+v, want %+v", o.Groups, want)
+	}
+	for i := range want {
+		if o.Groups[i] != want[i] {
+			t.Errorf("group %d = %+v, want %+v", i, o.Groups[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Package gl1 draws two triangles with different programs
+package gl1
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+
+	"app"
+	"glutil"
+)
+
+var t1Format = glutil.NewVertexFormat(glutil.Position3f)
+
+var t1 = []float32{
+	-0.25, -0.25, 0.0,
+	0.25, -0.25, 0.0,
+	0.0, 0.25, 0.0,
+}
+
+var t2 = []float32{
+	0.25, 0.5, 0.0,
+	-0.25, 0.5, 0.0,
+	0.0, 0.25, 0.0,
+}
+
+func keyCallback(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	if key == glfw.KeyEscape && action == glfw.Press {
+		w.SetShouldClose(true)
+	}
+}
+
+// scene draws two triangles with different programs
+type scene struct {
+	app.Base
+	tri1, tri2 *glutil.Mesh
+	p1, p2     *glutil.Program
+}
+
+func (s *scene) Init(a *app.App) error {
+	if window := a.Window(); window != nil {
+		window.SetKeyCallback(keyCallback)
+	}
+
+	var err error
+	if s.tri1, err = glutil.NewMesh(t1Format, t1, nil); err != nil {
+		return err
+	}
+	if s.tri2, err = glutil.NewMesh(t1Format, t2, nil); err != nil {
+		return err
+	}
+
+	// Load up a program
+	if s.p1, err = glutil.NewProgram("shaders/vert1.glsl", "shaders/frag1.glsl"); err != nil {
+		return err
+	}
+	if s.p2, err = glutil.NewProgram("shaders/vert1.glsl", "shaders/frag1a.glsl"); err != nil {
+		return err
+	}
+
+	return t1Format.Check(s.p1)
+}
+
+func (s *scene) Render(alpha float64) {
+	gl.ClearColor(0.3, 0.3, 0.3, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	s.p1.Use()
+
+	s.tri1.Draw()
+
+	s.p2.Use()
+
+	s.tri2.Draw()
+}
+
+func (s *scene) Shutdown() {
+	s.tri1.Delete()
+	s.tri2.Delete()
+	s.p1.Delete()
+	s.p2.Delete()
+}
+
+func init() {
+	app.Register(app.Entry{
+		Name:        "gl1",
+		Description: "Two triangles drawn with different programs",
+		Width:       640,
+		Height:      480,
+		New:         func() app.Scene { return &scene{} },
+	})
+}
@@ -0,0 +1,84 @@
+// Package gl2 slides a triangle from side to side
+package gl2
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+
+	"app"
+	"glutil"
+)
+
+var t1Format = glutil.NewVertexFormat(glutil.Position3f, glutil.Color3f)
+
+var t1 = []float32{
+	-0.25, -0.25, 0.0, 1.0, 0.0, 0.0,
+	0.25, -0.25, 0.0, 0.0, 1.0, 0.0,
+	0.0, 0.25, 0.0, 0.0, 0.0, 1.0,
+}
+
+func keyCallback(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	if key == glfw.KeyEscape && action == glfw.Press {
+		w.SetShouldClose(true)
+	}
+}
+
+// scene slides a triangle from side to side
+type scene struct {
+	app.Base
+	app  *app.App
+	tri1 *glutil.Mesh
+	p1   *glutil.Program
+}
+
+func (s *scene) Init(a *app.App) error {
+	s.app = a
+	if window := a.Window(); window != nil {
+		window.SetKeyCallback(keyCallback)
+	}
+
+	var err error
+	if s.tri1, err = glutil.NewMesh(t1Format, t1, nil); err != nil {
+		return err
+	}
+
+	// Load up a program
+	if s.p1, err = glutil.NewProgram("shaders/vert2.glsl", "shaders/frag2.glsl"); err != nil {
+		return err
+	}
+
+	if err := t1Format.Check(s.p1); err != nil {
+		return err
+	}
+
+	s.p1.Use()
+	return nil
+}
+
+func (s *scene) Render(alpha float64) {
+	gl.ClearColor(0.3, 0.3, 0.3, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	timeValue := s.app.Time()
+	horizOffset := float32((math.Sin(timeValue) / 2) + 0.5)
+	s.p1.SetFloat("horizOffset", horizOffset)
+
+	s.tri1.Draw()
+}
+
+func (s *scene) Shutdown() {
+	s.tri1.Delete()
+	s.p1.Delete()
+}
+
+func init() {
+	app.Register(app.Entry{
+		Name:        "gl2",
+		Description: "A triangle sliding from side to side",
+		Width:       640,
+		Height:      480,
+		New:         func() app.Scene { return &scene{} },
+	})
+}
@@ -0,0 +1,107 @@
+// Package gl3 draws a quad blending two textures
+package gl3
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+
+	"app"
+	"glutil"
+)
+
+var t1Format = glutil.NewVertexFormat(glutil.Position3f, glutil.Color3f, glutil.UV2f)
+
+var t1 = []float32{
+	// Positions   Colors    Texture Coords
+	0.5, 0.5, 0.0, 1.0, 0.0, 0.0, 1.0, 1.0,
+	0.5, -0.5, 0.0, 0.0, 1.0, 0.0, 1.0, 0.0,
+	-0.5, -0.5, 0.0, 0.0, 0.0, 1.0, 0.0, 0.0,
+	-0.5, 0.5, 0.0, 1.0, 1.0, 0.0, 0.0, 1.0,
+}
+
+var t1Indices = []uint32{
+	0, 1, 3,
+	1, 2, 3,
+}
+
+func keyCallback(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	if key == glfw.KeyEscape && action == glfw.Press {
+		w.SetShouldClose(true)
+	}
+}
+
+// scene draws a quad blending two textures
+type scene struct {
+	app.Base
+	app      *app.App
+	quad     *glutil.Mesh
+	p1       *glutil.Program
+	textures [2]uint32
+}
+
+func (s *scene) Init(a *app.App) error {
+	s.app = a
+	if window := a.Window(); window != nil {
+		window.SetKeyCallback(keyCallback)
+	}
+
+	var err error
+	if s.quad, err = glutil.NewMesh(t1Format, t1, t1Indices); err != nil {
+		return err
+	}
+
+	// Load up a program
+	if s.p1, err = glutil.NewProgram("shaders/vert3.glsl", "shaders/frag3.glsl"); err != nil {
+		return err
+	}
+
+	if err := t1Format.Check(s.p1); err != nil {
+		return err
+	}
+
+	s.p1.Use()
+
+	for i, name := range []string{"textures/container.jpg", "textures/awesomeface.png"} {
+		if s.textures[i], err = glutil.LoadTexture(name); err != nil {
+			fmt.Printf("%+v\n", err)
+		}
+	}
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, s.textures[0])
+	s.p1.SetSampler("texture1", 0)
+	gl.ActiveTexture(gl.TEXTURE1)
+	gl.BindTexture(gl.TEXTURE_2D, s.textures[1])
+	s.p1.SetSampler("texture2", 1)
+	return nil
+}
+
+func (s *scene) Render(alpha float64) {
+	timeValue := s.app.Time()
+	horizOffset := float32((math.Sin(timeValue) / 2) + 0.5)
+	s.p1.SetFloat("horizOffset", horizOffset)
+
+	gl.ClearColor(0.3, 0.3, 0.3, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+
+	s.quad.Draw()
+}
+
+func (s *scene) Shutdown() {
+	s.quad.Delete()
+	s.p1.Delete()
+	gl.DeleteTextures(int32(len(s.textures)), &s.textures[0])
+}
+
+func init() {
+	app.Register(app.Entry{
+		Name:        "gl3",
+		Description: "A quad blending two textures",
+		Width:       640,
+		Height:      480,
+		New:         func() app.Scene { return &scene{} },
+	})
+}
@@ -0,0 +1,155 @@
+// Package gl4 spins a textured cube
+package gl4
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"app"
+	"glutil"
+)
+
+var t1Format = glutil.NewVertexFormat(glutil.Position3f, glutil.UV2f)
+
+var t1 = []float32{
+	-0.5, -0.5, -0.5, 0.0, 0.0,
+	0.5, -0.5, -0.5, 1.0, 0.0,
+	0.5, 0.5, -0.5, 1.0, 1.0,
+	0.5, 0.5, -0.5, 1.0, 1.0,
+	-0.5, 0.5, -0.5, 0.0, 1.0,
+	-0.5, -0.5, -0.5, 0.0, 0.0,
+
+	-0.5, -0.5, 0.5, 0.0, 0.0,
+	0.5, -0.5, 0.5, 1.0, 0.0,
+	0.5, 0.5, 0.5, 1.0, 1.0,
+	0.5, 0.5, 0.5, 1.0, 1.0,
+	-0.5, 0.5, 0.5, 0.0, 1.0,
+	-0.5, -0.5, 0.5, 0.0, 0.0,
+
+	-0.5, 0.5, 0.5, 1.0, 0.0,
+	-0.5, 0.5, -0.5, 1.0, 1.0,
+	-0.5, -0.5, -0.5, 0.0, 1.0,
+	-0.5, -0.5, -0.5, 0.0, 1.0,
+	-0.5, -0.5, 0.5, 0.0, 0.0,
+	-0.5, 0.5, 0.5, 1.0, 0.0,
+
+	0.5, 0.5, 0.5, 1.0, 0.0,
+	0.5, 0.5, -0.5, 1.0, 1.0,
+	0.5, -0.5, -0.5, 0.0, 1.0,
+	0.5, -0.5, -0.5, 0.0, 1.0,
+	0.5, -0.5, 0.5, 0.0, 0.0,
+	0.5, 0.5, 0.5, 1.0, 0.0,
+
+	-0.5, -0.5, -0.5, 0.0, 1.0,
+	0.5, -0.5, -0.5, 1.0, 1.0,
+	0.5, -0.5, 0.5, 1.0, 0.0,
+	0.5, -0.5, 0.5, 1.0, 0.0,
+	-0.5, -0.5, 0.5, 0.0, 0.0,
+	-0.5, -0.5, -0.5, 0.0, 1.0,
+
+	-0.5, 0.5, -0.5, 0.0, 1.0,
+	0.5, 0.5, -0.5, 1.0, 1.0,
+	0.5, 0.5, 0.5, 1.0, 0.0,
+	0.5, 0.5, 0.5, 1.0, 0.0,
+	-0.5, 0.5, 0.5, 0.0, 0.0,
+	-0.5, 0.5, -0.5, 0.0, 1.0,
+}
+
+func keyCallback(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	if key == glfw.KeyEscape && action == glfw.Press {
+		w.SetShouldClose(true)
+	}
+}
+
+// scene spins a textured cube
+type scene struct {
+	app.Base
+	app      *app.App
+	cube     *glutil.Mesh
+	p1       *glutil.Program
+	textures [2]uint32
+}
+
+func (s *scene) Init(a *app.App) error {
+	s.app = a
+	if window := a.Window(); window != nil {
+		window.SetKeyCallback(keyCallback)
+	}
+
+	var err error
+	if s.cube, err = glutil.NewMesh(t1Format, t1, nil); err != nil {
+		return err
+	}
+
+	// Load up a program
+	if s.p1, err = glutil.NewProgram("shaders/vert4.glsl", "shaders/frag4.glsl"); err != nil {
+		return err
+	}
+
+	if err := t1Format.Check(s.p1); err != nil {
+		return err
+	}
+
+	s.p1.Use()
+
+	for i, name := range []string{"textures/container.jpg", "textures/awesomeface.png"} {
+		if s.textures[i], err = glutil.LoadTexture(name); err != nil {
+			fmt.Printf("%+v\n", err)
+		}
+	}
+
+	gl.Enable(gl.DEPTH_TEST)
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, s.textures[0])
+	s.p1.SetSampler("texture1", 0)
+	gl.ActiveTexture(gl.TEXTURE1)
+	gl.BindTexture(gl.TEXTURE_2D, s.textures[1])
+	s.p1.SetSampler("texture2", 1)
+
+	// Rotate vertices around X-axis -55 degrees
+	//model := mgl32.HomogRotate3D(mgl32.DegToRad(-55.0), mgl32.Vec3{1.0, 0.0, 0.0})
+	// Step back -3
+	view := mgl32.Translate3D(0.0, 0.0, -3.0)
+	s.p1.SetMat4("view", view)
+	return nil
+}
+
+// Resize projects using the framebuffer's real aspect ratio
+func (s *scene) Resize(width, height int) {
+	s.p1.SetMat4("projection", mgl32.Perspective(45.0, s.app.Viewport().Aspect(), 0.1, 100.0))
+}
+
+func (s *scene) Render(alpha float64) {
+	t := float32(s.app.Time())
+	model := mgl32.HomogRotate3D(mgl32.DegToRad(t*50.0), mgl32.Vec3{0.5, 1.0, 0.0})
+	s.p1.SetMat4("model", model)
+
+	// transform = transform.Mul4(mgl32.Scale3D(0.75, 0.75, 0.75))
+	// gl.UniformMatrix4fv(transformLoc, 1, false, (*float32)(unsafe.Pointer(&transform[0])))
+	// transformLoc := gl.GetUniformLocation(p1.ID, gl.Str("transform\x00"))
+
+	gl.ClearColor(0.3, 0.3, 0.3, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	s.cube.Draw()
+}
+
+func (s *scene) Shutdown() {
+	s.cube.Delete()
+	s.p1.Delete()
+	gl.DeleteTextures(int32(len(s.textures)), &s.textures[0])
+}
+
+func init() {
+	app.Register(app.Entry{
+		Name:        "gl4",
+		Description: "A spinning textured cube",
+		Width:       640,
+		Height:      480,
+		New:         func() app.Scene { return &scene{} },
+	})
+}
@@ -1,4 +1,4 @@
-package main
+package gl5
 
 import (
 	"math"
@@ -16,10 +16,28 @@ const (
 	MoveBackward
 	MoveLeft
 	MoveRight
+	MoveUp
+	MoveDown
+	RollLeft
+	RollRight
 )
 
+// Viewer is a camera the render loop can drive, so it can switch between
+// free flight and orbiting at runtime
+type Viewer interface {
+	viewMatrix() mgl32.Mat4
+	projectionMatrix() mgl32.Mat4
+	setAspect(aspect float32)
+	toggleOrtho()
+	processKeyboard(direction CameraMovement, deltaTime float32)
+	processMousePos(xoffset, yoffset float64)
+	processScroll(yoffset float64)
+}
+
 // Camera is a thing
 type Camera struct {
+	Projection
+
 	movementSpeed float64
 	sensitivity   float64
 
@@ -28,20 +46,23 @@ type Camera struct {
 
 	position mgl32.Vec3
 	front    mgl32.Vec3
+	right    mgl32.Vec3
 	up       mgl32.Vec3
 	worldUp  mgl32.Vec3
 }
 
 func newCamera() *Camera {
-	return &Camera{
+	c := &Camera{
+		Projection:    newProjection(),
 		movementSpeed: 3.0,
 		sensitivity:   0.15,
 		position:      mgl32.Vec3{0.0, 0.0, 3.0},
-		up:            mgl32.Vec3{0.0, 1.0, 0.0},
-		front:         mgl32.Vec3{0.0, 0.0, -1.0},
-		yaw:           0.0,
+		worldUp:       mgl32.Vec3{0.0, 1.0, 0.0},
+		yaw:           -90.0,
 		pitch:         0.0,
 	}
+	c.updateVectors()
+	return c
 }
 
 func (c *Camera) viewMatrix() mgl32.Mat4 {
@@ -56,9 +77,13 @@ func (c *Camera) processKeyboard(direction CameraMovement, deltaTime float32) {
 	case MoveBackward:
 		c.position = c.position.Sub(c.front.Mul(velocity))
 	case MoveLeft:
-		c.position = c.position.Sub(c.front.Cross(c.up).Normalize().Mul(velocity))
+		c.position = c.position.Sub(c.right.Mul(velocity))
 	case MoveRight:
-		c.position = c.position.Add(c.front.Cross(c.up).Normalize().Mul(velocity))
+		c.position = c.position.Add(c.right.Mul(velocity))
+	case MoveUp:
+		c.position = c.position.Add(c.worldUp.Mul(velocity))
+	case MoveDown:
+		c.position = c.position.Sub(c.worldUp.Mul(velocity))
 	}
 }
 
@@ -74,6 +99,11 @@ func (c *Camera) processMousePos(xoffset, yoffset float64) {
 	c.updateVectors()
 }
 
+// processScroll zooms by changing the field of view
+func (c *Camera) processScroll(yoffset float64) {
+	c.zoom(yoffset)
+}
+
 func (c *Camera) updateVectors() {
 	yawR := mgl64.DegToRad(c.yaw)
 	pitchR := mgl64.DegToRad(c.pitch)
@@ -84,4 +114,8 @@ func (c *Camera) updateVectors() {
 	front[2] = float32(math.Cos(pitchR) * math.Sin(yawR))
 
 	c.front = front.Normalize()
+
+	// Pitch is clamped short of the poles, so front is never parallel to worldUp
+	c.right = c.front.Cross(c.worldUp).Normalize()
+	c.up = c.right.Cross(c.front)
 }
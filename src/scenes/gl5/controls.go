@@ -0,0 +1,170 @@
+package gl5
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// lookSpeed is how far a fully pushed look stick turns the camera per
+// second, in the same units as mouse movement
+const lookSpeed = 600.0
+
+// defaultBindings are gl5's controls until -input overrides them. Joystick
+// numbering follows an Xbox style pad on Linux: left stick axes 0/1, right
+// stick 3/4, triggers 2/5, A B X Y buttons 0-3, bumpers 4/5 and start 7.
+// bookmark_N and save_bookmark_N for N in 1-9 are added by init.
+var defaultBindings = map[string][]string{
+	"quit":             {"Escape"},
+	"move_forward":     {"W", "Up", "Axis1-"},
+	"move_backward":    {"S", "Down", "Axis1+"},
+	"move_left":        {"A", "Left", "Axis0-"},
+	"move_right":       {"D", "Right", "Axis0+"},
+	"move_up":          {"Space", "Axis5+"},
+	"move_down":        {"LeftControl", "Axis2+"},
+	"roll_left":        {"Q", "Button4"},
+	"roll_right":       {"E", "Button5"},
+	"look_left":        {"Axis3-"},
+	"look_right":       {"Axis3+"},
+	"look_up":          {"Axis4-"},
+	"look_down":        {"Axis4+"},
+	"orbit_rotate":     {"MouseLeft"},
+	"orbit_pan":        {"MouseMiddle"},
+	"switch_camera":    {"C", "Button7"},
+	"frame_scene":      {"F", "Button3"},
+	"toggle_ortho":     {"O"},
+	"toggle_wireframe": {"X"},
+	"screenshot":       {"F12"},
+	"record":           {"F9"},
+	"record_path":      {"P"},
+	"play_path":        {"L"},
+	"pause":            {"Pause", "F5"},
+	"step_frame":       {"Period", "F6"},
+}
+
+func init() {
+	for i := 1; i <= 9; i++ {
+		defaultBindings[fmt.Sprintf("bookmark_%d", i)] = []string{fmt.Sprint(i)}
+		defaultBindings[fmt.Sprintf("save_bookmark_%d", i)] = []string{fmt.Sprintf("Shift+%d", i)}
+	}
+}
+
+// loadControls binds the defaults and then anything in the -input file
+func (s *scene) loadControls() error {
+	s.controls.Deadzone = float32(s.deadzone)
+	s.controls.Curve = float32(s.curve)
+
+	if err := s.controls.BindAll(defaultBindings); err != nil {
+		return err
+	}
+	if s.inputFile != "" {
+		return s.controls.Load(s.inputFile)
+	}
+	return nil
+}
+
+// handleActions acts on the actions started this frame
+func (s *scene) handleActions() {
+	a := s.app
+	w := a.Window()
+	controls, loop := s.controls, a.Loop
+	if controls.Pressed("quit") {
+		a.Quit()
+	}
+	if controls.Pressed("screenshot") {
+		s.screenshotRequested = true
+	}
+	if controls.Pressed("record") {
+		s.recordToggled = true
+	}
+	if controls.Pressed("record_path") {
+		s.pathRecordToggled = true
+	}
+	if controls.Pressed("play_path") {
+		s.pathPlayToggled = true
+	}
+	if controls.Pressed("switch_camera") {
+		s.switchCamera(w)
+	}
+	if controls.Pressed("frame_scene") {
+		s.frameRequested = true
+	}
+	if controls.Pressed("toggle_ortho") {
+		s.viewer.toggleOrtho()
+	}
+	if controls.Pressed("pause") {
+		loop.TogglePause()
+		if loop.Paused {
+			fmt.Printf("Paused\n")
+		} else {
+			fmt.Printf("Resumed\n")
+		}
+	}
+	if controls.Pressed("step_frame") {
+		loop.StepOnce()
+	}
+	if controls.Pressed("toggle_wireframe") {
+		s.wireframe = !s.wireframe
+		if s.wireframe {
+			gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
+		} else {
+			gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+		}
+	}
+
+	for i := 1; i <= 9; i++ {
+		if controls.Pressed(fmt.Sprintf("save_bookmark_%d", i)) {
+			s.useBookmark(w, fmt.Sprint(i), true)
+		}
+		if controls.Pressed(fmt.Sprintf("bookmark_%d", i)) {
+			s.useBookmark(w, fmt.Sprint(i), false)
+		}
+	}
+
+	s.orbit.rotating = s.viewer == Viewer(s.orbit) && controls.Held("orbit_rotate")
+	s.orbit.panning = s.viewer == Viewer(s.orbit) && controls.Held("orbit_pan")
+}
+
+// movements are the actions that move the active camera
+var movements = []struct {
+	action    string
+	direction CameraMovement
+}{
+	{"move_forward", MoveForward},
+	{"move_backward", MoveBackward},
+	{"move_left", MoveLeft},
+	{"move_right", MoveRight},
+	{"move_up", MoveUp},
+	{"move_down", MoveDown},
+	{"roll_left", RollLeft},
+	{"roll_right", RollRight},
+}
+
+// doMovement moves the active camera, scaling each movement by how far its
+// action is applied so analog sticks give partial speed
+func (s *scene) doMovement(deltaTime float32) {
+	for _, m := range movements {
+		if v := s.controls.Value(m.action); v > 0 {
+			s.viewer.processKeyboard(m.direction, deltaTime*v)
+		}
+	}
+
+	// The look stick stands in for the mouse, turning at a rate rather than
+	// by a distance. The orbit camera only turns while rotating.
+	x := s.controls.Axis("look_left", "look_right")
+	y := s.controls.Axis("look_down", "look_up")
+	if x != 0 || y != 0 {
+		rotating := s.orbit.rotating
+		s.orbit.rotating = true
+		s.viewer.processMousePos(float64(x*deltaTime)*lookSpeed, float64(y*deltaTime)*lookSpeed)
+		s.orbit.rotating = rotating
+	}
+}
+
+// setCursor changes the cursor mode of the window, if there is one
+func setCursor(w *glfw.Window, mode int) {
+	if w != nil {
+		w.SetInputMode(glfw.CursorMode, mode)
+	}
+}
@@ -0,0 +1,89 @@
+package gl5
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// FlightCamera is a six degrees of freedom camera for space-style scenes.
+// Its orientation is a quaternion, so it can roll and loop over the top
+// without the gimbal lock yaw/pitch angles suffer from.
+type FlightCamera struct {
+	Projection
+
+	movementSpeed float64
+	sensitivity   float64
+	rollSpeed     float64
+
+	position mgl32.Vec3
+
+	// orientation turns camera space, looking down -Z with +Y up, into world space
+	orientation mgl32.Quat
+}
+
+func newFlightCamera(position mgl32.Vec3) *FlightCamera {
+	return &FlightCamera{
+		Projection:    newProjection(),
+		movementSpeed: 3.0,
+		sensitivity:   0.15,
+		rollSpeed:     90.0,
+		position:      position,
+		orientation:   mgl32.QuatIdent(),
+	}
+}
+
+func (c *FlightCamera) front() mgl32.Vec3 { return c.orientation.Rotate(mgl32.Vec3{0.0, 0.0, -1.0}) }
+func (c *FlightCamera) right() mgl32.Vec3 { return c.orientation.Rotate(mgl32.Vec3{1.0, 0.0, 0.0}) }
+func (c *FlightCamera) up() mgl32.Vec3    { return c.orientation.Rotate(mgl32.Vec3{0.0, 1.0, 0.0}) }
+
+func (c *FlightCamera) viewMatrix() mgl32.Mat4 {
+	p := c.position
+	return c.orientation.Inverse().Mat4().Mul4(mgl32.Translate3D(-p[0], -p[1], -p[2]))
+}
+
+// rotate turns the camera by degrees about an axis in its own space
+func (c *FlightCamera) rotate(degrees float64, axis mgl32.Vec3) {
+	q := mgl32.QuatRotate(mgl32.DegToRad(float32(degrees)), axis)
+	c.orientation = c.orientation.Mul(q).Normalize()
+}
+
+func (c *FlightCamera) processKeyboard(direction CameraMovement, deltaTime float32) {
+	velocity := float32(c.movementSpeed) * deltaTime
+	switch direction {
+	case MoveForward:
+		c.position = c.position.Add(c.front().Mul(velocity))
+	case MoveBackward:
+		c.position = c.position.Sub(c.front().Mul(velocity))
+	case MoveLeft:
+		c.position = c.position.Sub(c.right().Mul(velocity))
+	case MoveRight:
+		c.position = c.position.Add(c.right().Mul(velocity))
+	case MoveUp:
+		c.position = c.position.Add(c.up().Mul(velocity))
+	case MoveDown:
+		c.position = c.position.Sub(c.up().Mul(velocity))
+	case RollLeft:
+		c.rotate(c.rollSpeed*float64(deltaTime), mgl32.Vec3{0.0, 0.0, 1.0})
+	case RollRight:
+		c.rotate(-c.rollSpeed*float64(deltaTime), mgl32.Vec3{0.0, 0.0, 1.0})
+	}
+}
+
+// processMousePos yaws about the camera's own up axis and pitches about its
+// own right axis, so mouse look stays relative to the view however it is rolled
+func (c *FlightCamera) processMousePos(xoffset, yoffset float64) {
+	c.rotate(-xoffset*c.sensitivity, mgl32.Vec3{0.0, 1.0, 0.0})
+	c.rotate(yoffset*c.sensitivity, mgl32.Vec3{1.0, 0.0, 0.0})
+}
+
+// processScroll zooms by changing the field of view
+func (c *FlightCamera) processScroll(yoffset float64) {
+	c.zoom(yoffset)
+}
+
+// lookFrom places the camera at position facing front, e.g. to take over
+// from another camera
+func (c *FlightCamera) lookFrom(position, front, up mgl32.Vec3) {
+	c.position = position
+	view := mgl32.LookAtV(position, position.Add(front), up)
+	c.orientation = mgl32.Mat4ToQuat(view).Inverse().Normalize()
+}
@@ -0,0 +1,607 @@
+// Package gl5 is a camera playground: textured cubes, optionally a model, and
+// FPS, orbit and flight cameras with recording, replay and input mapping
+package gl5
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"app"
+	"glutil"
+	"input"
+	"model"
+)
+
+var t1Format = glutil.NewVertexFormat(glutil.Position3f, glutil.UV2f)
+
+var t1 = []float32{
+	-0.5, -0.5, -0.5, 0.0, 0.0,
+	0.5, -0.5, -0.5, 1.0, 0.0,
+	0.5, 0.5, -0.5, 1.0, 1.0,
+	0.5, 0.5, -0.5, 1.0, 1.0,
+	-0.5, 0.5, -0.5, 0.0, 1.0,
+	-0.5, -0.5, -0.5, 0.0, 0.0,
+
+	-0.5, -0.5, 0.5, 0.0, 0.0,
+	0.5, -0.5, 0.5, 1.0, 0.0,
+	0.5, 0.5, 0.5, 1.0, 1.0,
+	0.5, 0.5, 0.5, 1.0, 1.0,
+	-0.5, 0.5, 0.5, 0.0, 1.0,
+	-0.5, -0.5, 0.5, 0.0, 0.0,
+
+	-0.5, 0.5, 0.5, 1.0, 0.0,
+	-0.5, 0.5, -0.5, 1.0, 1.0,
+	-0.5, -0.5, -0.5, 0.0, 1.0,
+	-0.5, -0.5, -0.5, 0.0, 1.0,
+	-0.5, -0.5, 0.5, 0.0, 0.0,
+	-0.5, 0.5, 0.5, 1.0, 0.0,
+
+	0.5, 0.5, 0.5, 1.0, 0.0,
+	0.5, 0.5, -0.5, 1.0, 1.0,
+	0.5, -0.5, -0.5, 0.0, 1.0,
+	0.5, -0.5, -0.5, 0.0, 1.0,
+	0.5, -0.5, 0.5, 0.0, 0.0,
+	0.5, 0.5, 0.5, 1.0, 0.0,
+
+	-0.5, -0.5, -0.5, 0.0, 1.0,
+	0.5, -0.5, -0.5, 1.0, 1.0,
+	0.5, -0.5, 0.5, 1.0, 0.0,
+	0.5, -0.5, 0.5, 1.0, 0.0,
+	-0.5, -0.5, 0.5, 0.0, 0.0,
+	-0.5, -0.5, -0.5, 0.0, 1.0,
+
+	-0.5, 0.5, -0.5, 0.0, 1.0,
+	0.5, 0.5, -0.5, 1.0, 1.0,
+	0.5, 0.5, 0.5, 1.0, 0.0,
+	0.5, 0.5, 0.5, 1.0, 0.0,
+	-0.5, 0.5, 0.5, 0.0, 0.0,
+	-0.5, 0.5, -0.5, 0.0, 1.0,
+}
+
+var cubes = []mgl32.Vec3{
+	mgl32.Vec3{0.0, 0.0, 0.0},
+	mgl32.Vec3{2.0, 5.0, -15.0},
+	mgl32.Vec3{1.5, -2.2, -2.5},
+	mgl32.Vec3{3.8, -2.0, -12.3},
+	mgl32.Vec3{2.4, -0.4, -3.5},
+	mgl32.Vec3{1.7, 3.0, -7.5},
+	mgl32.Vec3{1.3, -2.0, -2.5},
+	mgl32.Vec3{1.5, 2.0, -2.5},
+	mgl32.Vec3{1.5, 0.2, -1.5},
+	mgl32.Vec3{1.3, 1.0, -1.5},
+}
+
+// options are gl5's command line settings
+type options struct {
+	modelFile string
+
+	record       bool
+	recordFormat string
+	recordFPS    int
+
+	bookmarkFile string
+	pathFile     string
+	play         bool
+
+	inputFile string
+	deadzone  float64
+	curve     float64
+
+	tapeFile   string
+	replayFile string
+}
+
+// defaultOptions are gl5's settings when no flags change them
+func defaultOptions() options {
+	return options{
+		recordFormat: "png",
+		recordFPS:    60,
+		bookmarkFile: "bookmarks.json",
+		pathFile:     "camera-path.json",
+		deadzone:     input.DefaultDeadzone,
+		curve:        input.DefaultCurve,
+	}
+}
+
+// register adds gl5's flags to a flag set, defaulting to the current options
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.modelFile, "model", o.modelFile, "glTF (.gltf, .glb) or OBJ model to draw at the origin")
+
+	fs.BoolVar(&o.record, "record", o.record, "start recording from the first frame (F9 toggles)")
+	fs.StringVar(&o.recordFormat, "recformat", o.recordFormat, "recording format: png (numbered frames) or y4m")
+	fs.IntVar(&o.recordFPS, "recfps", o.recordFPS, "frame rate, and so simulated timestep, of recordings")
+
+	fs.StringVar(&o.bookmarkFile, "bookmarks", o.bookmarkFile, "JSON file camera bookmarks are kept in")
+	fs.StringVar(&o.pathFile, "path", o.pathFile, "JSON file camera paths are recorded to and played from")
+	fs.BoolVar(&o.play, "play", o.play, "play the camera path from the first frame (L toggles)")
+
+	fs.StringVar(&o.inputFile, "input", o.inputFile, "JSON file of action bindings overriding the defaults")
+	fs.Float64Var(&o.deadzone, "deadzone", o.deadzone, "fraction of joystick axis travel ignored around the centre")
+	fs.Float64Var(&o.curve, "curve", o.curve, "exponent applied to joystick axes for finer control near the centre")
+
+	fs.StringVar(&o.tapeFile, "tape", o.tapeFile, "record every frame's input and delta time to this file, saved on exit")
+	fs.StringVar(&o.replayFile, "replay", o.replayFile, "replay input recorded with -tape instead of reading the window and joystick")
+}
+
+const gWidth = 800
+const gHeight = 600
+
+// How often a keyframe is taken while recording a camera path, in seconds
+const keyframeInterval = 0.25
+
+// scene is the camera playground: textured cubes, optionally a model, and
+// every camera, recording and input feature
+type scene struct {
+	options
+
+	app      *app.App
+	cubeMesh *glutil.Mesh
+	p1       *glutil.Program
+	textures [2]uint32
+	model    *model.Scene
+
+	camera *Camera
+	orbit  *OrbitCamera
+	flight *FlightCamera
+
+	// viewer is whichever of camera, orbit and flight is active; C cycles.
+	// views smooths frames between the app loop's fixed updates.
+	viewer Viewer
+	views  viewHistory
+
+	controls  *input.Map
+	inputTape *input.Tape
+	wireframe bool
+
+	lastX      float64
+	lastY      float64
+	firstMouse bool
+
+	// Set by the screenshot action, taken once the next frame is drawn
+	screenshotRequested bool
+
+	// Set by the record action to start or stop recording at the next frame
+	recordToggled bool
+	recorder      *glutil.Recorder
+
+	// Set by the frame_scene action to point the orbit camera at the whole scene
+	frameRequested bool
+
+	bookmarks Bookmarks
+
+	pathRecordToggled bool
+	pathPlayToggled   bool
+
+	cameraPath    *CameraPath
+	pathRecording bool
+	pathPlaying   bool
+	pathTime      float64
+}
+
+func newScene(opts options) *scene {
+	s := &scene{
+		options:    opts,
+		camera:     newCamera(),
+		orbit:      newOrbitCamera(mgl32.Vec3{}, 5.0),
+		flight:     newFlightCamera(mgl32.Vec3{0.0, 0.0, 3.0}),
+		controls:   input.NewMap(),
+		lastX:      gWidth / 2.0,
+		lastY:      gHeight / 2.0,
+		firstMouse: true,
+	}
+	s.viewer = s.camera
+	return s
+}
+
+func (s *scene) mouseCallback(w *glfw.Window, x, y float64) {
+	if s.controls.Replaying {
+		return
+	}
+	if s.taping() {
+		s.inputTape.Add(input.Event{Type: input.CursorInput, X: x, Y: y})
+	}
+	s.cursorMoved(x, y)
+}
+
+// cursorMoved turns the camera by how far the cursor moved since last time
+func (s *scene) cursorMoved(x, y float64) {
+	if s.firstMouse {
+		s.lastX = x
+		s.lastY = y
+		s.firstMouse = false
+	}
+
+	xoffset := x - s.lastX
+	yoffset := s.lastY - y
+
+	s.lastX = x
+	s.lastY = y
+
+	s.viewer.processMousePos(xoffset, yoffset)
+
+	// No updates run while paused to move the view on, so show the look now
+	if s.app.Loop.Paused {
+		s.views.reset(s.viewer)
+	}
+}
+
+func (s *scene) scrollCallback(w *glfw.Window, xoff, yoff float64) {
+	if s.controls.Replaying {
+		return
+	}
+	if s.taping() {
+		s.inputTape.Add(input.Event{Type: input.ScrollInput, X: xoff, Y: yoff})
+	}
+	s.viewer.processScroll(yoff)
+}
+
+// switchCamera cycles from the FPS camera to orbiting to free flight. Orbiting
+// needs the cursor to click and drag with, the others capture it for mouse look.
+func (s *scene) switchCamera(w *glfw.Window) {
+	switch s.viewer {
+	case Viewer(s.camera):
+		s.viewer = s.orbit
+		setCursor(w, glfw.CursorNormal)
+	case Viewer(s.orbit):
+		// Take off from wherever the FPS camera was
+		s.flight.lookFrom(s.camera.position, s.camera.front, s.camera.up)
+		s.viewer = s.flight
+		setCursor(w, glfw.CursorDisabled)
+	default:
+		s.viewer = s.camera
+	}
+	s.firstMouse = true
+}
+
+// sceneBounds is the box around the loaded model, or around the cubes when
+// there is none
+func sceneBounds(scene *model.Scene) (min, max mgl32.Vec3) {
+	if scene != nil {
+		return scene.Bounds()
+	}
+
+	half := mgl32.Vec3{0.5, 0.5, 0.5}
+	min, max = cubes[0].Sub(half), cubes[0].Add(half)
+	for _, c := range cubes {
+		for j := 0; j < 3; j++ {
+			if c[j]-0.5 < min[j] {
+				min[j] = c[j] - 0.5
+			}
+			if c[j]+0.5 > max[j] {
+				max[j] = c[j] + 0.5
+			}
+		}
+	}
+	return
+}
+
+func (s *scene) Init(a *app.App) error {
+	s.app = a
+
+	if err := s.loadControls(); err != nil {
+		return err
+	}
+	if err := s.startTape(); err != nil {
+		return err
+	}
+
+	if window := a.Window(); window != nil {
+		s.controls.Attach(window)
+		window.SetCursorPosCallback(s.mouseCallback)
+		window.SetScrollCallback(s.scrollCallback)
+		window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+	}
+
+	var err error
+	if s.cubeMesh, err = glutil.NewMesh(t1Format, t1, nil); err != nil {
+		return err
+	}
+
+	// Load up a program
+	if s.p1, err = glutil.NewProgram("shaders/vert4.glsl", "shaders/frag4.glsl"); err != nil {
+		return err
+	}
+
+	if err := t1Format.Check(s.p1); err != nil {
+		return err
+	}
+
+	s.p1.Use()
+
+	if s.modelFile != "" {
+		// A model named on the command line is a file on disk, not an asset
+		name, err := filepath.Abs(s.modelFile)
+		if err != nil {
+			return err
+		}
+		if s.model, err = model.Load(name, t1Format); err != nil {
+			return err
+		}
+	}
+
+	for i, name := range []string{"textures/container.jpg", "textures/awesomeface.png"} {
+		if s.textures[i], err = glutil.LoadTexture(name); err != nil {
+			fmt.Printf("%+v\n", err)
+		}
+	}
+
+	gl.Enable(gl.DEPTH_TEST)
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, s.textures[0])
+	gl.ActiveTexture(gl.TEXTURE1)
+	gl.BindTexture(gl.TEXTURE_2D, s.textures[1])
+
+	s.setStaticUniforms()
+
+	s.frameRequested = true
+
+	if s.bookmarks, err = loadBookmarks(s.bookmarkFile); err != nil {
+		fmt.Printf("Failed to load bookmarks: %v\n", err)
+		s.bookmarks = make(Bookmarks)
+	}
+
+	s.recordToggled = s.record
+	s.pathPlayToggled = s.play
+	return nil
+}
+
+// setStaticUniforms sets the uniforms that only change when the program is
+// (re)linked
+func (s *scene) setStaticUniforms() {
+	s.p1.SetSampler("texture1", 0)
+	s.p1.SetSampler("texture2", 1)
+}
+
+// Resize keeps every camera's aspect ratio in step with the window
+func (s *scene) Resize(width, height int) {
+	for _, v := range []Viewer{s.camera, s.orbit, s.flight} {
+		v.setAspect(s.app.Viewport().Aspect())
+	}
+}
+
+// BeginFrame handles the frame's input and picks its time step: a replayed
+// frame's delta time, or exactly one frame's time while recording
+func (s *scene) BeginFrame(frameTime float64) float64 {
+	replayDelta, replayed := 0.0, false
+	if s.controls.Replaying {
+		replayDelta, replayed = s.replayFrame()
+	}
+
+	s.controls.Update()
+	s.handleActions()
+
+	if s.recordToggled {
+		s.recordToggled = false
+		s.toggleRecording()
+	}
+	if s.pathRecordToggled {
+		s.pathRecordToggled = false
+		s.togglePathRecording()
+	}
+	if s.pathPlayToggled {
+		s.pathPlayToggled = false
+		s.togglePathPlayback(s.app.Window())
+	}
+
+	// Pick up edits to the shader sources without restarting
+	if s.p1.Poll() {
+		s.setStaticUniforms()
+	}
+
+	if replayed {
+		frameTime = replayDelta
+	} else if s.recorder != nil {
+		frameTime = s.recorder.Step
+	}
+	if s.taping() {
+		s.inputTape.EndFrame(frameTime)
+	}
+
+	if s.frameRequested {
+		s.frameRequested = false
+		min, max := sceneBounds(s.model)
+		s.orbit.frame(min, max)
+	}
+	return frameTime
+}
+
+// Update is one fixed step of the camera simulation
+func (s *scene) Update(dt float64) {
+	if s.pathPlaying {
+		s.playPath(dt)
+	} else {
+		s.doMovement(float32(dt))
+		if s.pathRecording {
+			s.recordPath(dt)
+		}
+	}
+	s.views.update(s.viewer)
+}
+
+func (s *scene) Render(alpha float64) {
+	s.p1.SetMat4("view", s.views.at(s.viewer, float32(alpha)))
+	s.p1.SetMat4("projection", s.viewer.projectionMatrix())
+
+	gl.ClearColor(0.3, 0.3, 0.3, 1.0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+
+	for _, cube := range cubes {
+		model := mgl32.Translate3D(cube[0], cube[1], cube[2])
+		s.p1.SetMat4("model", model)
+		s.cubeMesh.Draw()
+	}
+
+	if s.model != nil {
+		s.model.Draw(s.p1, "model", mgl32.Ident4())
+	}
+
+	viewport := s.app.Viewport()
+	if s.screenshotRequested {
+		s.screenshotRequested = false
+		if name, err := glutil.Screenshot(viewport); err != nil {
+			fmt.Printf("Screenshot failed: %v\n", err)
+		} else {
+			fmt.Printf("Saved %s\n", name)
+		}
+	}
+
+	if s.recorder != nil {
+		if err := s.recorder.Capture(viewport); err != nil {
+			fmt.Printf("Recording failed: %v\n", err)
+			s.toggleRecording()
+		}
+	}
+}
+
+func (s *scene) Shutdown() {
+	if s.recorder != nil {
+		s.toggleRecording()
+	}
+	s.stopTape()
+
+	if s.model != nil {
+		s.model.Delete()
+	}
+	s.cubeMesh.Delete()
+	s.p1.Delete()
+	gl.DeleteTextures(int32(len(s.textures)), &s.textures[0])
+}
+
+func init() {
+	opts := defaultOptions()
+	app.Register(app.Entry{
+		Name:        "gl5",
+		Description: "Cubes and models with FPS, orbit and flight cameras",
+		Width:       gWidth,
+		Height:      gHeight,
+		Flags:       opts.register,
+		New:         func() app.Scene { return newScene(opts) },
+	})
+}
+
+// toggleRecording starts a new recording under recordings/ or finishes the
+// current one
+func (s *scene) toggleRecording() {
+	if s.recorder != nil {
+		if err := s.recorder.Close(); err != nil {
+			fmt.Printf("Recording failed: %v\n", err)
+		}
+		fmt.Printf("Recorded %d frames to %s\n", s.recorder.Frames, s.recorder.Path)
+		s.recorder = nil
+		return
+	}
+
+	path := filepath.Join("recordings", time.Now().Format("20060102-150405"))
+	if s.recordFormat == "y4m" {
+		path += ".y4m"
+	}
+
+	r, err := glutil.NewRecorder(path, s.recordFPS)
+	if err != nil {
+		fmt.Printf("Recording failed: %v\n", err)
+		return
+	}
+	fmt.Printf("Recording to %s\n", path)
+	s.recorder = r
+}
+
+// useBookmark moves the FPS camera to a bookmark, or with save set stores its
+// current pose there
+func (s *scene) useBookmark(w *glfw.Window, name string, save bool) {
+	if save {
+		s.bookmarks[name] = s.camera.pose()
+		if err := s.bookmarks.save(s.bookmarkFile); err != nil {
+			fmt.Printf("Failed to save bookmarks: %v\n", err)
+			return
+		}
+		fmt.Printf("Saved bookmark %s\n", name)
+		return
+	}
+
+	pose, ok := s.bookmarks[name]
+	if !ok {
+		fmt.Printf("No bookmark %s\n", name)
+		return
+	}
+	s.camera.setPose(pose)
+	s.useCamera(w)
+}
+
+// useCamera makes the FPS camera the active one
+func (s *scene) useCamera(w *glfw.Window) {
+	if s.viewer == Viewer(s.camera) {
+		return
+	}
+	s.viewer = s.camera
+	setCursor(w, glfw.CursorDisabled)
+	s.firstMouse = true
+}
+
+// togglePathRecording starts taking keyframes of the FPS camera, or stops and
+// saves them to the path file
+func (s *scene) togglePathRecording() {
+	if s.pathRecording {
+		s.pathRecording = false
+		s.cameraPath.add(s.pathTime, s.camera.pose())
+		if err := s.cameraPath.save(s.pathFile); err != nil {
+			fmt.Printf("Failed to save camera path: %v\n", err)
+			return
+		}
+		fmt.Printf("Saved %d keyframes to %s\n", len(s.cameraPath.Keyframes), s.pathFile)
+		return
+	}
+
+	s.pathPlaying = false
+	s.pathRecording = true
+	s.pathTime = 0
+	s.cameraPath = &CameraPath{}
+	s.cameraPath.add(0, s.camera.pose())
+	fmt.Printf("Recording camera path\n")
+}
+
+func (s *scene) recordPath(deltaTime float64) {
+	s.pathTime += deltaTime
+	last := s.cameraPath.Keyframes[len(s.cameraPath.Keyframes)-1]
+	if s.pathTime-last.Time >= keyframeInterval {
+		s.cameraPath.add(s.pathTime, s.camera.pose())
+	}
+}
+
+// togglePathPlayback plays the path file on the FPS camera, or stops
+func (s *scene) togglePathPlayback(w *glfw.Window) {
+	if s.pathPlaying {
+		s.pathPlaying = false
+		return
+	}
+	if s.pathRecording {
+		s.togglePathRecording()
+	}
+
+	path, err := loadCameraPath(s.pathFile)
+	if err != nil {
+		fmt.Printf("Failed to load camera path: %v\n", err)
+		return
+	}
+	s.cameraPath = path
+	s.pathPlaying = true
+	s.pathTime = 0
+	s.useCamera(w)
+	fmt.Printf("Playing %s (%.1fs)\n", s.pathFile, path.duration())
+}
+
+// playPath moves the FPS camera along the path, stopping at its end
+func (s *scene) playPath(deltaTime float64) {
+	s.pathTime += deltaTime
+	s.camera.setPose(s.cameraPath.sample(s.pathTime))
+	if s.pathTime >= s.cameraPath.duration() {
+		s.pathPlaying = false
+		fmt.Printf("Camera path finished\n")
+	}
+}
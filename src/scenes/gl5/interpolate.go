@@ -0,0 +1,61 @@
+package gl5
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// viewHistory keeps the active camera's view after each of the last two
+// updates, so frames drawn between updates can show it part way between them
+type viewHistory struct {
+	viewer     Viewer
+	prev, curr mgl32.Mat4
+}
+
+// reset holds the view still at v's current view
+func (h *viewHistory) reset(v Viewer) {
+	h.viewer = v
+	h.prev = v.viewMatrix()
+	h.curr = h.prev
+}
+
+// update records the view of the active camera v after an update
+func (h *viewHistory) update(v Viewer) {
+	if h.viewer != v {
+		h.reset(v)
+		return
+	}
+	h.prev = h.curr
+	h.curr = v.viewMatrix()
+}
+
+// at is the view alpha of the way from the second last update to the last.
+// Switching the active camera v jumps straight to the new one's view.
+func (h *viewHistory) at(v Viewer, alpha float32) mgl32.Mat4 {
+	if h.viewer != v {
+		h.reset(v)
+	}
+	return interpolateView(h.prev, h.curr, alpha)
+}
+
+// interpolateView blends two view matrices by moving the eye in a straight
+// line and slerping its orientation, which unlike blending the matrices'
+// elements keeps the result a rotation
+func interpolateView(a, b mgl32.Mat4, alpha float32) mgl32.Mat4 {
+	if alpha <= 0 {
+		return a
+	}
+	if alpha >= 1 {
+		return b
+	}
+
+	qa, qb := mgl32.Mat4ToQuat(a), mgl32.Mat4ToQuat(b)
+	if qa.Dot(qb) < 0 {
+		// Take the short way round
+		qb = qb.Scale(-1)
+	}
+	eyeA, eyeB := a.Inv().Col(3).Vec3(), b.Inv().Col(3).Vec3()
+
+	eye := eyeA.Add(eyeB.Sub(eyeA).Mul(alpha))
+	rotation := mgl32.QuatSlerp(qa, qb, alpha).Normalize().Mat4()
+	return rotation.Mul4(mgl32.Translate3D(-eye[0], -eye[1], -eye[2]))
+}
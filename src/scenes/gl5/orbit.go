@@ -0,0 +1,134 @@
+package gl5
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// OrbitCamera circles a target point for inspecting models. Left-drag
+// rotates, middle-drag pans and the scroll wheel zooms.
+type OrbitCamera struct {
+	Projection
+
+	sensitivity float64
+	zoomSpeed   float64
+	panSpeed    float32
+
+	// yaw and pitch in degrees place the camera around the target
+	yaw      float64
+	pitch    float64
+	distance float32
+	target   mgl32.Vec3
+
+	minDistance float32
+
+	// Set from the mouse buttons by handleActions
+	rotating bool
+	panning  bool
+}
+
+func newOrbitCamera(target mgl32.Vec3, distance float32) *OrbitCamera {
+	return &OrbitCamera{
+		Projection:  newProjection(),
+		sensitivity: 0.3,
+		zoomSpeed:   0.9,
+		panSpeed:    0.0015,
+		pitch:       20.0,
+		distance:    distance,
+		target:      target,
+		minDistance: 0.1,
+	}
+}
+
+func (c *OrbitCamera) position() mgl32.Vec3 {
+	yawR := mgl64.DegToRad(c.yaw)
+	pitchR := mgl64.DegToRad(c.pitch)
+
+	offset := mgl32.Vec3{
+		float32(math.Cos(pitchR) * math.Sin(yawR)),
+		float32(math.Sin(pitchR)),
+		float32(math.Cos(pitchR) * math.Cos(yawR)),
+	}
+	return c.target.Add(offset.Mul(c.distance))
+}
+
+func (c *OrbitCamera) viewMatrix() mgl32.Mat4 {
+	return mgl32.LookAtV(c.position(), c.target, mgl32.Vec3{0.0, 1.0, 0.0})
+}
+
+// processKeyboard zooms with forward/back and circles with left/right
+func (c *OrbitCamera) processKeyboard(direction CameraMovement, deltaTime float32) {
+	switch direction {
+	case MoveForward:
+		c.dolly(float64(deltaTime) * 5.0)
+	case MoveBackward:
+		c.dolly(-float64(deltaTime) * 5.0)
+	case MoveLeft:
+		c.yaw -= 90.0 * float64(deltaTime)
+	case MoveRight:
+		c.yaw += 90.0 * float64(deltaTime)
+	}
+}
+
+func (c *OrbitCamera) processMousePos(xoffset, yoffset float64) {
+	if c.rotating {
+		c.yaw -= xoffset * c.sensitivity
+		c.pitch -= yoffset * c.sensitivity
+		c.pitch = math.Max(-89.0, math.Min(89.0, c.pitch))
+	}
+
+	if c.panning {
+		// Move the target in the view plane so the scene follows the cursor,
+		// scaled by distance so panning feels the same at any zoom
+		front := c.target.Sub(c.position()).Normalize()
+		right := front.Cross(mgl32.Vec3{0.0, 1.0, 0.0}).Normalize()
+		up := right.Cross(front)
+
+		scale := c.panSpeed * c.distance
+		c.target = c.target.Sub(right.Mul(float32(xoffset) * scale))
+		c.target = c.target.Sub(up.Mul(float32(yoffset) * scale))
+	}
+}
+
+// projectionMatrix sizes the orthographic view to the target's distance, so
+// toggling between the two modes keeps the target the same size
+func (c *OrbitCamera) projectionMatrix() mgl32.Mat4 {
+	c.focus = c.distance
+	return c.Projection.projectionMatrix()
+}
+
+// processScroll moves towards or away from the target rather than changing
+// the field of view, so the perspective stays natural up close
+func (c *OrbitCamera) processScroll(yoffset float64) {
+	c.dolly(yoffset)
+}
+
+// dolly moves steps notches along the view axis, each a fixed fraction of the
+// distance so it slows down approaching the target
+func (c *OrbitCamera) dolly(steps float64) {
+	c.distance *= float32(math.Pow(c.zoomSpeed, steps))
+	if c.distance < c.minDistance {
+		c.distance = c.minDistance
+	}
+}
+
+// frame aims at the centre of a bounding box from far enough away that the
+// whole box fits in the field of view
+func (c *OrbitCamera) frame(min, max mgl32.Vec3) {
+	fovy := mgl32.DegToRad(c.fov)
+	if c.aspect < 1 {
+		// The horizontal field of view is the narrower one
+		fovy = 2 * float32(math.Atan(math.Tan(float64(fovy)/2)*float64(c.aspect)))
+	}
+
+	c.target = min.Add(max).Mul(0.5)
+
+	radius := max.Sub(min).Len() / 2
+	if radius <= 0 {
+		radius = 1
+	}
+	c.distance = radius / float32(math.Sin(float64(fovy)/2))
+	c.minDistance = radius * 0.01
+}
@@ -0,0 +1,169 @@
+package gl5
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// Pose is where the FPS camera is and which way it faces
+type Pose struct {
+	Position mgl32.Vec3 `json:"position"`
+	Yaw      float64    `json:"yaw"`
+	Pitch    float64    `json:"pitch"`
+}
+
+func (c *Camera) pose() Pose {
+	return Pose{Position: c.position, Yaw: c.yaw, Pitch: c.pitch}
+}
+
+func (c *Camera) setPose(p Pose) {
+	c.position = p.Position
+	c.yaw = p.Yaw
+	c.pitch = math.Max(-89.0, math.Min(89.0, p.Pitch))
+	c.updateVectors()
+}
+
+// orientation is the rotation from looking down -Z to the pose's direction
+func (p Pose) orientation() mgl32.Quat {
+	yawR := mgl64.DegToRad(p.Yaw)
+	pitchR := mgl64.DegToRad(p.Pitch)
+	front := mgl32.Vec3{
+		float32(math.Cos(pitchR) * math.Cos(yawR)),
+		float32(math.Sin(pitchR)),
+		float32(math.Cos(pitchR) * math.Sin(yawR)),
+	}
+	view := mgl32.LookAtV(mgl32.Vec3{}, front, mgl32.Vec3{0.0, 1.0, 0.0})
+	return mgl32.Mat4ToQuat(view).Inverse().Normalize()
+}
+
+// setOrientation points the pose the way q faces, dropping any roll
+func (p *Pose) setOrientation(q mgl32.Quat) {
+	front := q.Rotate(mgl32.Vec3{0.0, 0.0, -1.0})
+	p.Pitch = mgl64.RadToDeg(math.Asin(float64(mgl32.Clamp(front[1], -1, 1))))
+	p.Yaw = mgl64.RadToDeg(math.Atan2(float64(front[2]), float64(front[0])))
+}
+
+// Bookmarks are named camera poses kept between runs
+type Bookmarks map[string]Pose
+
+// loadBookmarks reads bookmarks from a JSON file. A missing file is an
+// empty set of bookmarks.
+func loadBookmarks(filename string) (Bookmarks, error) {
+	b := make(Bookmarks)
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return b, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b Bookmarks) save(filename string) error {
+	return saveJSON(filename, b)
+}
+
+// Keyframe is a pose at a time in seconds from the start of a camera path
+type Keyframe struct {
+	Time float64 `json:"time"`
+	Pose
+}
+
+// CameraPath is a recorded fly-through. Playback follows a Catmull-Rom spline
+// through the keyframe positions and slerps between their orientations.
+type CameraPath struct {
+	Keyframes []Keyframe `json:"keyframes"`
+}
+
+func loadCameraPath(filename string) (*CameraPath, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	p := &CameraPath{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *CameraPath) save(filename string) error {
+	return saveJSON(filename, p)
+}
+
+func (p *CameraPath) add(t float64, pose Pose) {
+	p.Keyframes = append(p.Keyframes, Keyframe{Time: t, Pose: pose})
+}
+
+func (p *CameraPath) duration() float64 {
+	if len(p.Keyframes) == 0 {
+		return 0
+	}
+	return p.Keyframes[len(p.Keyframes)-1].Time
+}
+
+// sample is the pose t seconds into the path, holding the first and last
+// keyframes outside it
+func (p *CameraPath) sample(t float64) Pose {
+	k := p.Keyframes
+	switch {
+	case len(k) == 0:
+		return Pose{}
+	case t <= k[0].Time:
+		return k[0].Pose
+	case t >= k[len(k)-1].Time:
+		return k[len(k)-1].Pose
+	}
+
+	i := 0
+	for i+2 < len(k) && k[i+1].Time <= t {
+		i++
+	}
+	u := float32((t - k[i].Time) / (k[i+1].Time - k[i].Time))
+
+	// The spline runs through k[i] and k[i+1]; the ends repeat their keyframe
+	// in place of a missing neighbour
+	p0, p1, p2, p3 := k[i].Position, k[i].Position, k[i+1].Position, k[i+1].Position
+	if i > 0 {
+		p0 = k[i-1].Position
+	}
+	if i+2 < len(k) {
+		p3 = k[i+2].Position
+	}
+
+	q1, q2 := k[i].orientation(), k[i+1].orientation()
+	if q1.Dot(q2) < 0 {
+		// Take the short way round
+		q2 = q2.Scale(-1)
+	}
+
+	pose := Pose{Position: catmullRom(p0, p1, p2, p3, u)}
+	pose.setOrientation(mgl32.QuatSlerp(q1, q2, u))
+	return pose
+}
+
+// catmullRom interpolates between p1 and p2 on a uniform Catmull-Rom spline
+func catmullRom(p0, p1, p2, p3 mgl32.Vec3, u float32) mgl32.Vec3 {
+	u2, u3 := u*u, u*u*u
+	a := p1.Mul(2)
+	b := p2.Sub(p0).Mul(u)
+	c := p0.Mul(2).Sub(p1.Mul(5)).Add(p2.Mul(4)).Sub(p3).Mul(u2)
+	d := p1.Mul(3).Sub(p0).Sub(p2.Mul(3)).Add(p3).Mul(u3)
+	return a.Add(b).Add(c).Add(d).Mul(0.5)
+}
+
+func saveJSON(filename string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
@@ -0,0 +1,60 @@
+package gl5
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Projection is the lens a camera looks through. Each camera embeds its own,
+// so switching cameras keeps each one's zoom.
+type Projection struct {
+	// fov is the vertical field of view in degrees
+	fov    float32
+	minFov float32
+	maxFov float32
+
+	near   float32
+	far    float32
+	aspect float32
+
+	// In orthographic mode the view is sized to match the perspective one at
+	// focus units from the camera
+	ortho bool
+	focus float32
+}
+
+func newProjection() Projection {
+	return Projection{
+		fov:    45.0,
+		minFov: 1.0,
+		maxFov: 90.0,
+		near:   0.1,
+		far:    100.0,
+		aspect: 1.0,
+		focus:  5.0,
+	}
+}
+
+func (p *Projection) projectionMatrix() mgl32.Mat4 {
+	if p.ortho {
+		h := p.focus * float32(math.Tan(float64(mgl32.DegToRad(p.fov))/2))
+		w := h * p.aspect
+		return mgl32.Ortho(-w, w, -h, h, p.near, p.far)
+	}
+	return mgl32.Perspective(mgl32.DegToRad(p.fov), p.aspect, p.near, p.far)
+}
+
+func (p *Projection) setAspect(aspect float32) {
+	p.aspect = aspect
+}
+
+func (p *Projection) toggleOrtho() {
+	p.ortho = !p.ortho
+}
+
+// zoom narrows the field of view by a degree per step, within its limits
+func (p *Projection) zoom(steps float64) {
+	p.fov -= float32(steps)
+	p.fov = mgl32.Clamp(p.fov, p.minFov, p.maxFov)
+}
@@ -0,0 +1,69 @@
+package gl5
+
+import (
+	"fmt"
+
+	"input"
+)
+
+// startTape sets up recording or replaying input from the flags
+func (s *scene) startTape() error {
+	switch {
+	case s.replayFile != "":
+		t, err := input.LoadTape(s.replayFile)
+		if err != nil {
+			return err
+		}
+		s.inputTape = t
+		s.controls.Replaying = true
+		fmt.Printf("Replaying %d frames from %s\n", len(t.Frames), s.replayFile)
+	case s.tapeFile != "":
+		s.inputTape = &input.Tape{}
+		s.controls.Tape = s.inputTape
+	}
+	return nil
+}
+
+// stopTape saves the input recorded with -tape
+func (s *scene) stopTape() {
+	if s.controls.Tape == nil {
+		return
+	}
+	if err := s.inputTape.Save(s.tapeFile); err != nil {
+		fmt.Printf("Failed to save input: %v\n", err)
+		return
+	}
+	fmt.Printf("Saved %d frames of input to %s\n", len(s.inputTape.Frames), s.tapeFile)
+}
+
+// replayFrame feeds the next frame of the tape to the controls and cameras
+// as if it had come from the window, returning the frame's delta time. Once
+// the tape runs out the window takes over again.
+func (s *scene) replayFrame() (float64, bool) {
+	f, ok := s.inputTape.Next()
+	if !ok {
+		s.controls.Replaying = false
+		fmt.Printf("Replay finished\n")
+		return 0, false
+	}
+
+	for _, e := range f.Events {
+		switch e.Type {
+		case input.KeyInput:
+			s.controls.KeyEvent(e.Key, e.Action)
+		case input.ButtonInput:
+			s.controls.MouseButtonEvent(e.Button, e.Action)
+		case input.CursorInput:
+			s.cursorMoved(e.X, e.Y)
+		case input.ScrollInput:
+			s.viewer.processScroll(e.Y)
+		}
+	}
+	s.controls.SetJoystickState(f.Axes, f.Buttons)
+	return f.Delta, true
+}
+
+// taping reports whether window input is being recorded
+func (s *scene) taping() bool {
+	return s.controls.Tape != nil
+}
@@ -0,0 +1,250 @@
+package shapes
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// All shapes are centred on the origin with Y up
+
+// Cube is an axis aligned cube with each face split into divisions x divisions quads
+func Cube(size float32, divisions int) *Shape {
+	divisions = clampMin(divisions, 1)
+	h := size / 2
+
+	// Each face's normal with right and up axes as seen from outside
+	faces := [][3]mgl32.Vec3{
+		{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}},
+		{{0, 0, -1}, {-1, 0, 0}, {0, 1, 0}},
+		{{1, 0, 0}, {0, 0, -1}, {0, 1, 0}},
+		{{-1, 0, 0}, {0, 0, 1}, {0, 1, 0}},
+		{{0, 1, 0}, {1, 0, 0}, {0, 0, -1}},
+		{{0, -1, 0}, {1, 0, 0}, {0, 0, 1}},
+	}
+
+	s := &Shape{}
+	for _, f := range faces {
+		normal, right, up := f[0], f[1], f[2]
+		s.grid(divisions, divisions, func(u float32, row int) (mgl32.Vec3, mgl32.Vec3, float32) {
+			v := float32(row) / float32(divisions)
+			pos := normal.Mul(h).Add(right.Mul((u - 0.5) * size)).Add(up.Mul((v - 0.5) * size))
+			return pos, normal, v
+		})
+	}
+	s.computeTangents()
+	return s
+}
+
+// Plane is a width x depth rectangle in the XZ plane facing +Y
+func Plane(width, depth float32, divisionsX, divisionsZ int) *Shape {
+	divisionsX, divisionsZ = clampMin(divisionsX, 1), clampMin(divisionsZ, 1)
+
+	s := &Shape{}
+	s.grid(divisionsX, divisionsZ, func(u float32, row int) (mgl32.Vec3, mgl32.Vec3, float32) {
+		v := float32(row) / float32(divisionsZ)
+		return mgl32.Vec3{(u - 0.5) * width, 0, (0.5 - v) * depth}, mgl32.Vec3{0, 1, 0}, v
+	})
+	s.computeTangents()
+	return s
+}
+
+// spherePoint is the unit vector at longitude theta and latitude phi, with
+// theta = 0 on +Z and increasing towards +X
+func spherePoint(theta, phi float64) mgl32.Vec3 {
+	return mgl32.Vec3{
+		float32(math.Cos(phi) * math.Sin(theta)),
+		float32(math.Sin(phi)),
+		float32(math.Cos(phi) * math.Cos(theta)),
+	}
+}
+
+// UVSphere is a latitude/longitude sphere
+func UVSphere(radius float32, segments, rings int) *Shape {
+	segments, rings = clampMin(segments, 3), clampMin(rings, 2)
+
+	s := &Shape{}
+	s.grid(segments, rings, func(u float32, row int) (mgl32.Vec3, mgl32.Vec3, float32) {
+		v := float32(row) / float32(rings)
+		n := spherePoint(2*math.Pi*float64(u), math.Pi*float64(v)-math.Pi/2)
+		return n.Mul(radius), n, v
+	})
+	s.computeTangents()
+	return s
+}
+
+// Icosphere is a subdivided icosahedron, giving evenly sized triangles. UVs use
+// the same spherical mapping as UVSphere, with vertices split along the seam.
+func Icosphere(radius float32, subdivisions int) *Shape {
+	t := float32((1 + math.Sqrt(5)) / 2)
+	points := []mgl32.Vec3{
+		{-1, t, 0}, {1, t, 0}, {-1, -t, 0}, {1, -t, 0},
+		{0, -1, t}, {0, 1, t}, {0, -1, -t}, {0, 1, -t},
+		{t, 0, -1}, {t, 0, 1}, {-t, 0, -1}, {-t, 0, 1},
+	}
+	for i := range points {
+		points[i] = points[i].Normalize()
+	}
+	faces := [][3]int{
+		{0, 11, 5}, {0, 5, 1}, {0, 1, 7}, {0, 7, 10}, {0, 10, 11},
+		{1, 5, 9}, {5, 11, 4}, {11, 10, 2}, {10, 7, 6}, {7, 1, 8},
+		{3, 9, 4}, {3, 4, 2}, {3, 2, 6}, {3, 6, 8}, {3, 8, 9},
+		{4, 9, 5}, {2, 4, 11}, {6, 2, 10}, {8, 6, 7}, {9, 8, 1},
+	}
+
+	// Split every triangle into four, sharing midpoints between neighbours
+	for i := 0; i < subdivisions; i++ {
+		midpoints := make(map[[2]int]int)
+		midpoint := func(a, b int) int {
+			key := [2]int{a, b}
+			if a > b {
+				key = [2]int{b, a}
+			}
+			if m, ok := midpoints[key]; ok {
+				return m
+			}
+			points = append(points, points[a].Add(points[b]).Normalize())
+			midpoints[key] = len(points) - 1
+			return len(points) - 1
+		}
+
+		next := make([][3]int, 0, len(faces)*4)
+		for _, f := range faces {
+			ab, bc, ca := midpoint(f[0], f[1]), midpoint(f[1], f[2]), midpoint(f[2], f[0])
+			next = append(next,
+				[3]int{f[0], ab, ca}, [3]int{f[1], bc, ab}, [3]int{f[2], ca, bc}, [3]int{ab, bc, ca})
+		}
+		faces = next
+	}
+
+	s := &Shape{}
+	for _, p := range points {
+		u := math.Atan2(float64(p[0]), float64(p[2])) / (2 * math.Pi)
+		if u < 0 {
+			u++
+		}
+		v := 0.5 + math.Asin(float64(p[1]))/math.Pi
+		s.vertex(p.Mul(radius), p, mgl32.Vec2{float32(u), float32(v)})
+	}
+
+	// Triangles straddling the u=0/1 seam get copies of their low-u vertices
+	// shifted by one, so the texture does not wrap backwards across them
+	seam := make(map[uint32]uint32)
+	for _, f := range faces {
+		idx := [3]uint32{uint32(f[0]), uint32(f[1]), uint32(f[2])}
+		minU, maxU := float32(1), float32(0)
+		for _, i := range idx {
+			if u := s.UVs[i][0]; u < minU {
+				minU = u
+			}
+			if u := s.UVs[i][0]; u > maxU {
+				maxU = u
+			}
+		}
+		if maxU-minU > 0.5 {
+			for k, i := range idx {
+				if s.UVs[i][0] >= 0.5 {
+					continue
+				}
+				copied, ok := seam[i]
+				if !ok {
+					s.Positions = append(s.Positions, s.Positions[i])
+					s.Normals = append(s.Normals, s.Normals[i])
+					s.UVs = append(s.UVs, mgl32.Vec2{s.UVs[i][0] + 1, s.UVs[i][1]})
+					copied = uint32(len(s.Positions) - 1)
+					seam[i] = copied
+				}
+				idx[k] = copied
+			}
+		}
+		s.triangle(idx[0], idx[1], idx[2])
+	}
+	s.computeTangents()
+	return s
+}
+
+// Cylinder is a capped cylinder of the given height along Y
+func Cylinder(radius, height float32, segments, stacks int) *Shape {
+	segments, stacks = clampMin(segments, 3), clampMin(stacks, 1)
+
+	s := &Shape{}
+	s.grid(segments, stacks, func(u float32, row int) (mgl32.Vec3, mgl32.Vec3, float32) {
+		v := float32(row) / float32(stacks)
+		n := spherePoint(2*math.Pi*float64(u), 0)
+		return mgl32.Vec3{radius * n[0], (v - 0.5) * height, radius * n[2]}, n, v
+	})
+	s.disc(radius, height/2, segments, true)
+	s.disc(radius, -height/2, segments, false)
+	s.computeTangents()
+	return s
+}
+
+// Cone has its base on y = -height/2 and its apex on y = height/2
+func Cone(radius, height float32, segments, stacks int) *Shape {
+	segments, stacks = clampMin(segments, 3), clampMin(stacks, 1)
+
+	// The side normal tilts up by the slope of the cone
+	slope := mgl32.Vec2{height, radius}.Normalize()
+
+	s := &Shape{}
+	s.grid(segments, stacks, func(u float32, row int) (mgl32.Vec3, mgl32.Vec3, float32) {
+		v := float32(row) / float32(stacks)
+		dir := spherePoint(2*math.Pi*float64(u), 0)
+		r := radius * (1 - v)
+		pos := mgl32.Vec3{r * dir[0], (v - 0.5) * height, r * dir[2]}
+		normal := mgl32.Vec3{slope[0] * dir[0], slope[1], slope[0] * dir[2]}
+		return pos, normal, v
+	})
+	s.disc(radius, -height/2, segments, false)
+	s.computeTangents()
+	return s
+}
+
+// Torus lies in the XZ plane. majorRadius is from the centre to the middle of
+// the tube, minorRadius the radius of the tube itself.
+func Torus(majorRadius, minorRadius float32, majorSegments, minorSegments int) *Shape {
+	majorSegments, minorSegments = clampMin(majorSegments, 3), clampMin(minorSegments, 3)
+
+	s := &Shape{}
+	s.grid(majorSegments, minorSegments, func(u float32, row int) (mgl32.Vec3, mgl32.Vec3, float32) {
+		v := float32(row) / float32(minorSegments)
+		n := spherePoint(2*math.Pi*float64(u), 2*math.Pi*float64(v))
+		ring := spherePoint(2*math.Pi*float64(u), 0).Mul(majorRadius)
+		return ring.Add(n.Mul(minorRadius)), n, v
+	})
+	s.computeTangents()
+	return s
+}
+
+// Capsule is a cylinder of the given height along Y closed by two hemispheres,
+// so its total height is height + 2*radius. rings is per hemisphere.
+func Capsule(radius, height float32, segments, rings int) *Shape {
+	segments, rings = clampMin(segments, 3), clampMin(rings, 1)
+
+	// v is spread by arc length so the texture is not stretched over the cylinder
+	arc := float32(math.Pi / 2 * float64(radius))
+	total := 2*arc + height
+
+	s := &Shape{}
+	s.grid(segments, 2*rings+1, func(u float32, row int) (mgl32.Vec3, mgl32.Vec3, float32) {
+		// Rows 0..rings are the bottom hemisphere, the rest the top one
+		var phi float64
+		var y, v float32
+		if row <= rings {
+			f := float32(row) / float32(rings)
+			phi = -math.Pi/2 + math.Pi/2*float64(f)
+			y = -height / 2
+			v = f * arc / total
+		} else {
+			f := float32(row-rings-1) / float32(rings)
+			phi = math.Pi / 2 * float64(f)
+			y = height / 2
+			v = (arc + height + f*arc) / total
+		}
+
+		n := spherePoint(2*math.Pi*float64(u), phi)
+		return n.Mul(radius).Add(mgl32.Vec3{0, y, 0}), n, v
+	})
+	s.computeTangents()
+	return s
+}
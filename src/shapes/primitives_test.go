@@ -0,0 +1,80 @@
+package shapes
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestPrimitives(t *testing.T) {
+	type shapeCase struct {
+		name      string
+		shape     *Shape
+		triangles int
+	}
+
+	var tests []shapeCase
+	for _, n := range []struct{ a, b, sub int }{{3, 2, 0}, {4, 3, 1}, {16, 12, 3}} {
+		a, b, sub := n.a, n.b, n.sub
+		tests = append(tests,
+			shapeCase{fmt.Sprintf("cube %d", a), Cube(2, a), 6 * a * a * 2},
+			shapeCase{fmt.Sprintf("plane %dx%d", a, b), Plane(2, 3, a, b), a * b * 2},
+			shapeCase{fmt.Sprintf("uv sphere %dx%d", a, b), UVSphere(1, a, b), a * b * 2},
+			shapeCase{fmt.Sprintf("icosphere %d", sub), Icosphere(1, sub), 20 << (2 * uint(sub))},
+			shapeCase{fmt.Sprintf("cylinder %dx%d", a, b), Cylinder(1, 2, a, b), a*b*2 + 2*a},
+			shapeCase{fmt.Sprintf("cone %dx%d", a, b), Cone(1, 2, a, b), a*b*2 + a},
+			shapeCase{fmt.Sprintf("torus %dx%d", a, a), Torus(2, 0.5, a, a), a * a * 2},
+			shapeCase{fmt.Sprintf("capsule %dx%d", a, b), Capsule(0.5, 1, a, b), a * (2*b + 1) * 2},
+		)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.shape
+			if got := len(s.Indices); got != 3*tt.triangles {
+				t.Errorf("got %d indices, want %d", got, 3*tt.triangles)
+			}
+
+			n := len(s.Positions)
+			if len(s.Normals) != n || len(s.UVs) != n || len(s.Tangents) != n {
+				t.Fatalf("%d positions but %d normals, %d UVs and %d tangents",
+					n, len(s.Normals), len(s.UVs), len(s.Tangents))
+			}
+			for i, idx := range s.Indices {
+				if int(idx) >= n {
+					t.Fatalf("index %d is %d, past %d vertices", i, idx, n)
+				}
+			}
+
+			for i, normal := range s.Normals {
+				if l := normal.Len(); math.Abs(float64(l-1)) > 1e-4 {
+					t.Fatalf("normal %d %v has length %v", i, normal, l)
+				}
+				tangent := s.Tangents[i]
+				if l := tangent.Vec3().Len(); math.Abs(float64(l-1)) > 1e-4 {
+					t.Fatalf("tangent %d %v has length %v", i, tangent, l)
+				}
+				if d := tangent.Vec3().Dot(normal); math.Abs(float64(d)) > 1e-4 {
+					t.Fatalf("tangent %d %v is not perpendicular to normal %v", i, tangent, normal)
+				}
+				if tangent[3] != 1 && tangent[3] != -1 {
+					t.Fatalf("tangent %d has handedness %v", i, tangent[3])
+				}
+			}
+
+			// Counter-clockwise triangles face the way their vertex normals
+			// do. Triangles collapsed at poles and apexes have no facing.
+			for i := 0; i < len(s.Indices); i += 3 {
+				a, b, c := s.Indices[i], s.Indices[i+1], s.Indices[i+2]
+				face := s.Positions[b].Sub(s.Positions[a]).Cross(s.Positions[c].Sub(s.Positions[a]))
+				if face.Len() < 1e-6 {
+					continue
+				}
+				normal := s.Normals[a].Add(s.Normals[b]).Add(s.Normals[c])
+				if face.Dot(normal) <= 0 {
+					t.Fatalf("triangle %d (%d %d %d) winds clockwise", i/3, a, b, c)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Package shapes generates indexed meshes for common primitives
+package shapes
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"glutil"
+)
+
+// Shape is generated geometry with every attribute a vertex format might ask
+// for. Triangles wind counter-clockwise seen from outside. Tangents carry the
+// bitangent handedness in W.
+type Shape struct {
+	Positions []mgl32.Vec3
+	Normals   []mgl32.Vec3
+	UVs       []mgl32.Vec2
+	Tangents  []mgl32.Vec4
+	Indices   []uint32
+}
+
+// Vertices interleaves the shape's attributes in the given vertex format
+func (s *Shape) Vertices(format glutil.VertexFormat) ([]float32, error) {
+	vertices := make([]float32, 0, len(s.Positions)*format.Floats())
+	for i := range s.Positions {
+		for _, a := range format {
+			switch a {
+			case glutil.Position3f:
+				vertices = append(vertices, s.Positions[i][:]...)
+			case glutil.Normal3f:
+				vertices = append(vertices, s.Normals[i][:]...)
+			case glutil.UV2f:
+				vertices = append(vertices, s.UVs[i][:]...)
+			case glutil.Tangent4f:
+				vertices = append(vertices, s.Tangents[i][:]...)
+			default:
+				return nil, fmt.Errorf("shapes cannot provide vertex attribute %s", a.Name)
+			}
+		}
+	}
+	return vertices, nil
+}
+
+// Mesh uploads the shape as an indexed mesh in the given vertex format
+func (s *Shape) Mesh(format glutil.VertexFormat) (*glutil.Mesh, error) {
+	vertices, err := s.Vertices(format)
+	if err != nil {
+		return nil, err
+	}
+	return glutil.NewMesh(format, vertices, s.Indices)
+}
+
+func (s *Shape) vertex(pos, normal mgl32.Vec3, uv mgl32.Vec2) uint32 {
+	s.Positions = append(s.Positions, pos)
+	s.Normals = append(s.Normals, normal)
+	// Images are uploaded top row first, so v runs down the texture
+	s.UVs = append(s.UVs, mgl32.Vec2{uv[0], 1 - uv[1]})
+	return uint32(len(s.Positions) - 1)
+}
+
+func (s *Shape) triangle(a, b, c uint32) {
+	s.Indices = append(s.Indices, a, b, c)
+}
+
+// grid adds a (cols+1) x (rows+1) sheet of vertices. f maps a column's u in
+// [0,1] and a row index to a position, normal and the row's v. Seen from the
+// side the normals face, u must run left to right and v bottom to top.
+func (s *Shape) grid(cols, rows int, f func(u float32, row int) (pos, normal mgl32.Vec3, v float32)) {
+	base := uint32(len(s.Positions))
+	for r := 0; r <= rows; r++ {
+		for c := 0; c <= cols; c++ {
+			u := float32(c) / float32(cols)
+			pos, normal, v := f(u, r)
+			s.vertex(pos, normal, mgl32.Vec2{u, v})
+		}
+	}
+
+	stride := uint32(cols + 1)
+	for r := uint32(0); r < uint32(rows); r++ {
+		for c := uint32(0); c < uint32(cols); c++ {
+			i := base + r*stride + c
+			s.triangle(i, i+1, i+stride+1)
+			s.triangle(i, i+stride+1, i+stride)
+		}
+	}
+}
+
+// disc adds a flat cap of radius r at height y as a fan, facing up or down
+func (s *Shape) disc(radius, y float32, segments int, up bool) {
+	normal := mgl32.Vec3{0, -1, 0}
+	if up {
+		normal = mgl32.Vec3{0, 1, 0}
+	}
+
+	center := s.vertex(mgl32.Vec3{0, y, 0}, normal, mgl32.Vec2{0.5, 0.5})
+	first := uint32(len(s.Positions))
+	for i := 0; i <= segments; i++ {
+		theta := 2 * math.Pi * float64(i) / float64(segments)
+		sin, cos := float32(math.Sin(theta)), float32(math.Cos(theta))
+		s.vertex(mgl32.Vec3{radius * sin, y, radius * cos}, normal, mgl32.Vec2{0.5 + 0.5*sin, 0.5 - 0.5*cos})
+	}
+
+	for i := uint32(0); i < uint32(segments); i++ {
+		if up {
+			s.triangle(center, first+i, first+i+1)
+		} else {
+			s.triangle(center, first+i+1, first+i)
+		}
+	}
+}
+
+// computeTangents derives per-vertex tangents from positions and UVs, then
+// orthogonalises them against the normals
+func (s *Shape) computeTangents() {
+	tan := make([]mgl32.Vec3, len(s.Positions))
+	bitan := make([]mgl32.Vec3, len(s.Positions))
+
+	for i := 0; i+2 < len(s.Indices); i += 3 {
+		a, b, c := s.Indices[i], s.Indices[i+1], s.Indices[i+2]
+		e1, e2 := s.Positions[b].Sub(s.Positions[a]), s.Positions[c].Sub(s.Positions[a])
+		d1, d2 := s.UVs[b].Sub(s.UVs[a]), s.UVs[c].Sub(s.UVs[a])
+
+		det := d1[0]*d2[1] - d2[0]*d1[1]
+		if det == 0 {
+			continue
+		}
+		r := 1 / det
+		t := e1.Mul(d2[1]).Sub(e2.Mul(d1[1])).Mul(r)
+		b2 := e2.Mul(d1[0]).Sub(e1.Mul(d2[0])).Mul(r)
+		for _, v := range []uint32{a, b, c} {
+			tan[v] = tan[v].Add(t)
+			bitan[v] = bitan[v].Add(b2)
+		}
+	}
+
+	s.Tangents = make([]mgl32.Vec4, len(s.Positions))
+	for i, n := range s.Normals {
+		t := tan[i].Sub(n.Mul(n.Dot(tan[i])))
+		if t.Len() < 1e-6 {
+			// Degenerate UVs, e.g. at a pole; pick any vector perpendicular to n
+			t = perpendicular(n)
+		}
+		t = t.Normalize()
+
+		w := float32(1)
+		if n.Cross(t).Dot(bitan[i]) < 0 {
+			w = -1
+		}
+		s.Tangents[i] = t.Vec4(w)
+	}
+}
+
+func perpendicular(n mgl32.Vec3) mgl32.Vec3 {
+	if math.Abs(float64(n[0])) < 0.9 {
+		return n.Cross(mgl32.Vec3{1, 0, 0})
+	}
+	return n.Cross(mgl32.Vec3{0, 1, 0})
+}
+
+func clampMin(n, min int) int {
+	if n < min {
+		return min
+	}
+	return n
+}